@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// kind is the Go representation chosen for a schema.
+type kind int
+
+const (
+	kindAny kind = iota
+	kindString
+	kindFloat64
+	kindBool
+	kindMap
+	kindStruct
+	kindSlice
+)
+
+// typ describes the Go type generated for a schema.
+type typ struct {
+	kind     kind
+	nullable bool
+	name     string // the struct name for kindStruct
+	elem     *typ   // the element type for kindSlice
+}
+
+func (t *typ) goType() string {
+	switch t.kind {
+	case kindString:
+		return "string"
+	case kindFloat64:
+		return "float64"
+	case kindBool:
+		return "bool"
+	case kindMap:
+		return "map[string]interface{}"
+	case kindStruct:
+		return t.name
+	case kindSlice:
+		return "[]" + t.elem.goType()
+	}
+	return "interface{}"
+}
+
+// field is a struct field generated for an object property.
+type field struct {
+	name     string
+	key      string
+	typ      *typ
+	optional bool
+}
+
+// pointer reports whether the field is generated as a pointer, which is the
+// case for optional and nullable scalars and structs.
+func (f field) pointer() bool {
+	switch f.typ.kind {
+	case kindString, kindFloat64, kindBool, kindStruct:
+		return f.optional || f.typ.nullable
+	}
+	return false
+}
+
+type structType struct {
+	name   string
+	fields []field
+}
+
+type generator struct {
+	structs []*structType
+	names   map[string]bool
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for i := range t {
+			if s, ok := t[i].(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// exported turns a key into an exported Go identifier.
+func exported(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		_, _ = b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+func (g *generator) typeName(parent, name string) string {
+	if !g.names[name] {
+		g.names[name] = true
+		return name
+	}
+	base := parent + name
+	name = base
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.names[name] = true
+	return name
+}
+
+// resolve picks the Go type for a schema, generating structs for objects
+// with properties. The name is used for any struct generated.
+func (g *generator) resolve(schema map[string]interface{}, parent, name string) *typ {
+	t := &typ{}
+	var types []string
+	for _, s := range schemaTypes(schema) {
+		if s == "null" {
+			t.nullable = true
+			continue
+		}
+		types = append(types, s)
+	}
+	if len(types) != 1 {
+		return t
+	}
+	switch types[0] {
+	case "string":
+		t.kind = kindString
+	case "number", "integer":
+		t.kind = kindFloat64
+	case "boolean":
+		t.kind = kindBool
+	case "array":
+		t.kind = kindSlice
+		t.elem = &typ{}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			t.elem = g.resolve(items, parent, name)
+		}
+		if t.elem.nullable || t.elem.kind == kindSlice {
+			t.elem = &typ{}
+		}
+	case "object":
+		properties, ok := schema["properties"].(map[string]interface{})
+		if !ok || len(properties) == 0 {
+			t.kind = kindMap
+			return t
+		}
+		t.kind = kindStruct
+		t.name = g.typeName(parent, name)
+		st := &structType{name: t.name}
+		g.structs = append(g.structs, st)
+
+		required := make(map[string]bool)
+		if list, ok := schema["required"].([]interface{}); ok {
+			for i := range list {
+				if s, ok := list[i].(string); ok {
+					required[s] = true
+				}
+			}
+		}
+		keys := make([]string, 0, len(properties))
+		for k := range properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		used := make(map[string]bool)
+		for _, k := range keys {
+			fieldName := exported(k)
+			for i := 2; used[fieldName]; i++ {
+				fieldName = fmt.Sprintf("%s%d", exported(k), i)
+			}
+			used[fieldName] = true
+			prop, _ := properties[k].(map[string]interface{})
+			st.fields = append(st.fields, field{
+				name:     fieldName,
+				key:      k,
+				typ:      g.resolve(prop, t.name, fieldName),
+				optional: !required[k],
+			})
+		}
+	}
+	return t
+}
+
+// generate returns the formatted Go source for the structs and extraction
+// functions describing the schema.
+func generate(schema map[string]interface{}, pkg, name string) ([]byte, error) {
+	g := &generator{names: make(map[string]bool)}
+	root := g.resolve(schema, "", name)
+	if root.kind != kindStruct {
+		return nil, fmt.Errorf("the schema must describe an object with properties")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by pinata-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import \"github.com/robbiev/pinata\"\n")
+	for _, st := range g.structs {
+		fmt.Fprintf(&buf, "\ntype %s struct {\n", st.name)
+		for _, f := range st.fields {
+			goType := f.typ.goType()
+			if f.pointer() {
+				goType = "*" + goType
+			}
+			fmt.Fprintf(&buf, "%s %s `json:%q`\n", f.name, goType, f.key)
+		}
+		fmt.Fprintf(&buf, "}\n")
+	}
+	for _, st := range g.structs {
+		fmt.Fprintf(&buf, "\n// Extract%s extracts the fields of %s from the Pinata.\n", st.name, st.name)
+		fmt.Fprintf(&buf, "func Extract%s(stick pinata.Stick, p pinata.Pinata) %s {\n", st.name, st.name)
+		fmt.Fprintf(&buf, "var v %s\n", st.name)
+		for _, f := range st.fields {
+			if f.optional || f.typ.nullable {
+				fmt.Fprintf(&buf, "m, ok := p.Map()\n")
+				writeNotMap(&buf, "p")
+				break
+			}
+		}
+		for _, f := range st.fields {
+			writeField(&buf, f)
+		}
+		fmt.Fprintf(&buf, "return v\n}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+var accessors = map[kind]string{
+	kindString:  "String",
+	kindFloat64: "Float64",
+	kindBool:    "Bool",
+}
+
+func writeField(buf *bytes.Buffer, f field) {
+	key := fmt.Sprintf("%q", f.key)
+	// optional and nullable values are only extracted if they hold a value,
+	// slices and maps get a block of their own to scope their variables
+	block := f.optional || f.typ.nullable || f.typ.kind == kindSlice || f.typ.kind == kindMap
+	if f.optional || f.typ.nullable {
+		fmt.Fprintf(buf, "if raw, ok := m[%s]; ok && raw != nil {\n", key)
+	} else if block {
+		fmt.Fprintf(buf, "{\n")
+	}
+	switch f.typ.kind {
+	case kindString, kindFloat64, kindBool:
+		value := fmt.Sprintf("stick.Path%s(p, %s)", accessors[f.typ.kind], key)
+		if f.pointer() {
+			fmt.Fprintf(buf, "x := %s\nv.%s = &x\n", value, f.name)
+		} else {
+			fmt.Fprintf(buf, "v.%s = %s\n", f.name, value)
+		}
+	case kindStruct:
+		value := fmt.Sprintf("Extract%s(stick, stick.Path(p, %s))", f.typ.name, key)
+		if f.pointer() {
+			fmt.Fprintf(buf, "x := %s\nv.%s = &x\n", value, f.name)
+		} else {
+			fmt.Fprintf(buf, "v.%s = %s\n", f.name, value)
+		}
+	case kindSlice:
+		fmt.Fprintf(buf, "items := stick.Path(p, %s)\n", key)
+		fmt.Fprintf(buf, "s, ok := items.Slice()\n")
+		fmt.Fprintf(buf, "if !ok {\n")
+		fmt.Fprintf(buf, "// Index records that items does not hold a slice\n")
+		fmt.Fprintf(buf, "stick.Index(items, 0)\n")
+		fmt.Fprintf(buf, "}\n")
+		fmt.Fprintf(buf, "v.%s = make(%s, len(s))\n", f.name, f.typ.goType())
+		fmt.Fprintf(buf, "for i := range s {\n")
+		elem := f.typ.elem
+		switch elem.kind {
+		case kindString, kindFloat64, kindBool:
+			fmt.Fprintf(buf, "v.%s[i] = stick.Index%s(items, i)\n", f.name, accessors[elem.kind])
+		case kindStruct:
+			fmt.Fprintf(buf, "v.%s[i] = Extract%s(stick, stick.Index(items, i))\n", f.name, elem.name)
+		case kindMap:
+			fmt.Fprintf(buf, "x := stick.Index(items, i)\n")
+			fmt.Fprintf(buf, "v.%s[i], ok = x.Map()\n", f.name)
+			writeNotMap(buf, "x")
+		default:
+			fmt.Fprintf(buf, "v.%s[i] = stick.Index(items, i).Value()\n", f.name)
+		}
+		fmt.Fprintf(buf, "}\n")
+	case kindMap:
+		fmt.Fprintf(buf, "x := stick.Path(p, %s)\n", key)
+		fmt.Fprintf(buf, "var ok bool\n")
+		fmt.Fprintf(buf, "v.%s, ok = x.Map()\n", f.name)
+		writeNotMap(buf, "x")
+	default:
+		fmt.Fprintf(buf, "v.%s = stick.Path(p, %s).Value()\n", f.name, key)
+	}
+	if block {
+		fmt.Fprintf(buf, "}\n")
+	}
+}
+
+// writeNotMap writes code that records an error through the stick unless ok
+// is set, as the Pinata in the variable does not hold a map then. The stick
+// has no method to record an error directly, so it calls Path, which fails on
+// anything but a map.
+func writeNotMap(buf *bytes.Buffer, variable string) {
+	fmt.Fprintf(buf, "if !ok {\n")
+	fmt.Fprintf(buf, "// Path records that %s does not hold a map\n", variable)
+	fmt.Fprintf(buf, "stick.Path(%s)\n", variable)
+	fmt.Fprintf(buf, "}\n")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestGenerate(t *testing.T) {
+	var samples []pinata.Pinata
+	for _, sample := range []string{
+		`{"name": "Kevin", "address": {"city": null}, "tags": ["a"], "orders": [{"id": 1}]}`,
+		`{"name": "Bob", "address": {"city": "Gophertown"}, "tags": [], "orders": [], "nick": "b"}`,
+	} {
+		var v interface{}
+		if err := json.Unmarshal([]byte(sample), &v); err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, pinata.NewPinata(v))
+	}
+	schema, _ := pinata.InferSchema(samples...).Map()
+
+	src, err := generate(schema, "gophers", "Gopher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"package gophers",
+		"Nick    *string  `json:\"nick\"`",
+		"Orders  []Orders `json:\"orders\"`",
+		"City *string `json:\"city\"`",
+		"func ExtractGopher(stick pinata.Stick, p pinata.Pinata) Gopher {",
+		`v.Name = stick.PathString(p, "name")`,
+		`v.Address = ExtractAddress(stick, stick.Path(p, "address"))`,
+		`if raw, ok := m["nick"]; ok && raw != nil {`,
+		`v.Tags[i] = stick.IndexString(items, i)`,
+		`v.Orders[i] = ExtractOrders(stick, stick.Index(items, i))`,
+		`v.Id = stick.PathFloat64(p, "id")`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("generated code must contain %q", expected)
+		}
+	}
+	if t.Failed() {
+		t.Log(string(src))
+	}
+
+	if _, err := generate(map[string]interface{}{"type": "string"}, "gophers", "Gopher"); err == nil {
+		t.Error("a schema that does not describe an object must result in an error")
+	}
+}
+
+// extractMain runs the generated Extract functions on the documents passed as
+// arguments and prints the error or what was extracted.
+const extractMain = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/robbiev/pinata"
+)
+
+func main() {
+	for _, document := range os.Args[1:] {
+		var v interface{}
+		if err := json.Unmarshal([]byte(document), &v); err != nil {
+			panic(err)
+		}
+		stick := pinata.NewStick()
+		gopher := ExtractGopher(stick, pinata.NewPinata(v))
+		if err := stick.ClearError(); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(gopher.Name, len(gopher.Tags), len(gopher.Meta), len(gopher.Extras))
+	}
+}
+`
+
+func TestGenerateRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiling the generated code takes a while")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go tool is not available")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(`{"name": "Kevin", "address": {"city": null}, "tags": ["a"], "meta": {}, "extras": [{}]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	schema, _ := pinata.InferSchema(pinata.NewPinata(v)).Map()
+	src, err := generate(schema, "main", "Gopher")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"go.mod":    "module gophers\n\ngo 1.21\n\nrequire github.com/robbiev/pinata v0.0.0\n\nreplace github.com/robbiev/pinata => " + root + "\n",
+		"gopher.go": string(src),
+		"main.go":   extractMain,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	documents := []string{
+		`{"name": "Kevin", "address": {"city": "Gophertown"}, "tags": ["a", "b"], "meta": {"a": 1}, "extras": [{}]}`,
+		`{"name": "Kevin", "address": {"city": null}, "tags": "a", "meta": {}, "extras": []}`,
+		`{"name": "Kevin", "address": {"city": null}, "tags": [], "meta": [], "extras": []}`,
+		`{"name": "Kevin", "address": {"city": null}, "tags": [], "meta": {}, "extras": [1]}`,
+		`{"name": "Kevin", "address": "Gophertown", "tags": [], "meta": {}, "extras": []}`,
+	}
+	cmd := exec.Command(goTool, append([]string{"run", "."}, documents...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %s\n%s", err, output, src)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	expected := []string{
+		"Kevin 2 1 1",
+		`pinata: incompatible type (call this method on a slice pinata) at Index(0) at Path("tags")`,
+		`pinata: incompatible type (call this method on a map pinata) at Path() at Path("meta")`,
+		`pinata: incompatible type (call this method on a map pinata) at Path() at Index(0) at Path("extras")`,
+		`pinata: incompatible type (call this method on a map pinata) at Path() at Path("address")`,
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("document %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Command pinata-gen generates Go structs from sample JSON documents or a
+// JSON Schema, together with functions that extract them from a Pinata.
+//
+// Usage:
+//
+//	pinata-gen [-type name] [-package name] [-o file] [sample.json ...]
+//	pinata-gen -schema schema.json [-type name] [-package name] [-o file]
+//
+// Samples are read from the named files, or from standard input if there are
+// none. A file may hold several JSON documents one after the other. The
+// samples are merged with pinata.InferSchema, so keys missing from some
+// samples and null values result in pointer fields.
+//
+// For every object a struct is generated along with an Extract function that
+// reads it using a pinata.Stick, so errors keep pinata's context:
+//
+//	stick, p := pinata.New(m)
+//	order := ExtractOrder(stick, p)
+//	if err := stick.ClearError(); err != nil {
+//		...
+//	}
+//
+// Schemas may use type, properties, required and items. Values of mixed types
+// become interface{}.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/robbiev/pinata"
+)
+
+func main() {
+	typeName := flag.String("type", "Root", "name of the generated root `type`")
+	pkg := flag.String("package", "main", "`name` of the generated package")
+	schemaFile := flag.String("schema", "", "generate from a JSON Schema `file` instead of samples")
+	output := flag.String("o", "", "write to `file` instead of standard output")
+	flag.Parse()
+
+	if err := run(*typeName, *pkg, *schemaFile, *output, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "pinata-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(typeName, pkg, schemaFile, output string, files []string) error {
+	var schema pinata.Pinata
+	if schemaFile != "" {
+		docs, err := readFile(schemaFile)
+		if err != nil {
+			return err
+		}
+		if len(docs) != 1 {
+			return fmt.Errorf("%s: expected a single schema", schemaFile)
+		}
+		schema = docs[0]
+	} else {
+		var samples []pinata.Pinata
+		if len(files) == 0 {
+			docs, err := read(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("standard input: %v", err)
+			}
+			samples = docs
+		}
+		for _, file := range files {
+			docs, err := readFile(file)
+			if err != nil {
+				return err
+			}
+			samples = append(samples, docs...)
+		}
+		if len(samples) == 0 {
+			return fmt.Errorf("no samples")
+		}
+		schema = pinata.InferSchema(samples...)
+	}
+
+	m, ok := schema.Map()
+	if !ok {
+		return fmt.Errorf("the schema must be an object")
+	}
+	src, err := generate(m, pkg, typeName)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(output, src, 0666)
+}
+
+func readFile(name string) ([]pinata.Pinata, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	docs, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return docs, nil
+}
+
+func read(r io.Reader) ([]pinata.Pinata, error) {
+	var docs []pinata.Pinata
+	dec := json.NewDecoder(r)
+	for {
+		var v interface{}
+		if err := dec.Decode(&v); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		docs = append(docs, pinata.NewPinata(v))
+	}
+}
@@ -0,0 +1,149 @@
+package pinata
+
+import (
+	"math"
+	"strconv"
+)
+
+// CoercionRule selects which lenient conversions a Stick may apply when a
+// value does not have the type an accessor expects. Rules can be combined
+// with the | operator.
+type CoercionRule int
+
+const (
+	// CoerceString lets String accept numbers and bools and format them.
+	CoerceString CoercionRule = 1 << iota
+	// CoerceFloat64 lets Float64 parse strings holding a number.
+	CoerceFloat64
+	// CoerceBool lets Bool accept the strings "true", "false", "1" and "0".
+	CoerceBool
+	// CoerceUnwrap lets a slice holding a single element be used where a
+	// string, float64 or bool is expected.
+	CoerceUnwrap
+
+	// CoerceAll enables every coercion rule.
+	CoerceAll = CoerceString | CoerceFloat64 | CoerceBool | CoerceUnwrap
+)
+
+// WithCoercion enables the given coercion rules. Every coercion that takes
+// place is recorded and can be audited with Stick.Coercions.
+func WithCoercion(rules CoercionRule) Option {
+	return func(s *stick) {
+		s.coercion |= rules
+	}
+}
+
+// Coercion records a single lenient conversion made by a Stick.
+type Coercion struct {
+	rule    CoercionRule
+	context *ErrorContext
+	from    interface{}
+	to      interface{}
+}
+
+// Rule returns the rule that allowed the conversion.
+func (c Coercion) Rule() CoercionRule {
+	return c.rule
+}
+
+// Context returns the circumstances of the conversion, in the same form as
+// for an Error.
+func (c Coercion) Context() (ErrorContext, bool) {
+	if c.context != nil {
+		return *c.context, true
+	}
+	return ErrorContext{}, false
+}
+
+// From returns the original value.
+func (c Coercion) From() interface{} {
+	return c.from
+}
+
+// To returns the converted value.
+func (c Coercion) To() interface{} {
+	return c.to
+}
+
+func (s *stick) Coercions() []Coercion {
+	return s.coercions
+}
+
+func (s *stick) coerced(rule CoercionRule, p Pinata, methodName string, input func() []interface{}, to interface{}) {
+	s.coercions = append(s.coercions, Coercion{
+		rule: rule,
+		context: &ErrorContext{
+			methodName: methodName,
+			methodArgs: input,
+			source:     p.source,
+			next:       p.context,
+		},
+		from: p.Value(),
+		to:   to,
+	})
+}
+
+// unwrap returns the only element of a single element slice Pinata if the
+// CoerceUnwrap rule is enabled, otherwise it returns the Pinata as is.
+func (s *stick) unwrap(p Pinata, methodName string, input func() []interface{}) Pinata {
+	if s.coercion&CoerceUnwrap == 0 {
+		return p
+	}
+	slice, ok := p.Slice()
+	if !ok || len(slice) != 1 {
+		return p
+	}
+	s.visitIndex(slice, 0)
+	s.coerced(CoerceUnwrap, p, methodName, input, slice[0])
+	return p.derive(slice[0], p.context, p.sourceOf(slice, "0", p.source))
+}
+
+func (s *stick) coerceString(p Pinata, methodName string, input func() []interface{}) (string, bool) {
+	if s.coercion&CoerceString == 0 {
+		return "", false
+	}
+	var v string
+	switch t := p.Value().(type) {
+	case float64:
+		v = strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		v = strconv.FormatBool(t)
+	default:
+		return "", false
+	}
+	s.coerced(CoerceString, p, methodName, input, v)
+	return v, true
+}
+
+func (s *stick) coerceFloat64(p Pinata, methodName string, input func() []interface{}) (float64, bool) {
+	if s.coercion&CoerceFloat64 == 0 {
+		return 0, false
+	}
+	str, ok := p.Value().(string)
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, false
+	}
+	s.coerced(CoerceFloat64, p, methodName, input, v)
+	return v, true
+}
+
+func (s *stick) coerceBool(p Pinata, methodName string, input func() []interface{}) (bool, bool) {
+	if s.coercion&CoerceBool == 0 {
+		return false, false
+	}
+	var v bool
+	switch p.Value() {
+	case "true", "1":
+		v = true
+	case "false", "0":
+		v = false
+	default:
+		return false, false
+	}
+	s.coerced(CoerceBool, p, methodName, input, v)
+	return v, true
+}
@@ -0,0 +1,69 @@
+package pinata_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestCoercion(t *testing.T) {
+	const message = `
+	{
+		"Price": "12.5",
+		"Quantity": 3,
+		"Active": "1",
+		"Tags": ["sale"],
+		"Code": "abc"
+	}`
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(message), &m); err != nil {
+		t.Fatal(err)
+	}
+	p := pinata.NewPinata(m)
+
+	{
+		strict := pinata.NewStick()
+		strict.PathFloat64(p, "Price")
+		if err := strict.ClearError(); err == nil {
+			t.Error("numeric string must not be a float64 without coercion")
+		}
+	}
+
+	stick := pinata.NewStick(pinata.WithCoercion(pinata.CoerceAll))
+	if v := stick.PathFloat64(p, "Price"); v != 12.5 {
+		t.Error("Price must be coerced to 12.5, got", v)
+	}
+	if v := stick.PathString(p, "Quantity"); v != "3" {
+		t.Error("Quantity must be coerced to \"3\", got", v)
+	}
+	if v := stick.PathBool(p, "Active"); !v {
+		t.Error("Active must be coerced to true")
+	}
+	if v := stick.PathString(p, "Tags"); v != "sale" {
+		t.Error("Tags must be unwrapped to \"sale\", got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	coercions := stick.Coercions()
+	if len(coercions) != 4 {
+		t.Fatalf("expected 4 coercions, got %d", len(coercions))
+	}
+	if coercions[0].Rule() != pinata.CoerceFloat64 || coercions[0].From() != "12.5" || coercions[0].To() != 12.5 {
+		t.Error("unexpected first coercion", coercions[0])
+	}
+	ctx, ok := coercions[0].Context()
+	if !ok || ctx.MethodName() != "PathFloat64" {
+		t.Error("coercion context must name the method")
+	}
+
+	stick.PathFloat64(p, "Code")
+	if err := stick.ClearError(); err == nil {
+		t.Error("non-numeric string must not be coerced to a float64")
+	} else {
+		t.Log(err)
+	}
+}
@@ -0,0 +1,147 @@
+package pinata
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Constraint is a rule the value of a Pinata must satisfy, see Stick.Check.
+type Constraint struct {
+	name   string
+	advice string
+	check  func(interface{}) bool
+}
+
+// GoString returns the constraint the way it was created, for example
+// MaxLen(254).
+func (c Constraint) GoString() string {
+	return c.name
+}
+
+// length returns the number of characters in a string or the number of
+// elements in a slice or map (the bool indicates success).
+func length(v interface{}) (int, bool) {
+	switch t := v.(type) {
+	case string:
+		return utf8.RuneCountInString(t), true
+	case []interface{}:
+		return len(t), true
+	case map[string]interface{}:
+		return len(t), true
+	}
+	return 0, false
+}
+
+// NonEmpty requires a non-empty string, slice or map.
+func NonEmpty() Constraint {
+	return Constraint{
+		name:   "NonEmpty()",
+		advice: "must be a non-empty string, slice or map",
+		check: func(v interface{}) bool {
+			n, ok := length(v)
+			return ok && n > 0
+		},
+	}
+}
+
+// MinLen requires a string of at least n characters, or a slice or map of at
+// least n elements.
+func MinLen(n int) Constraint {
+	return Constraint{
+		name:   fmt.Sprintf("MinLen(%d)", n),
+		advice: fmt.Sprintf("must be a string, slice or map with a length of at least %d", n),
+		check: func(v interface{}) bool {
+			l, ok := length(v)
+			return ok && l >= n
+		},
+	}
+}
+
+// MaxLen requires a string of at most n characters, or a slice or map of at
+// most n elements.
+func MaxLen(n int) Constraint {
+	return Constraint{
+		name:   fmt.Sprintf("MaxLen(%d)", n),
+		advice: fmt.Sprintf("must be a string, slice or map with a length of at most %d", n),
+		check: func(v interface{}) bool {
+			l, ok := length(v)
+			return ok && l <= n
+		},
+	}
+}
+
+// Range requires a float64 between min and max inclusive.
+func Range(min, max float64) Constraint {
+	return Constraint{
+		name:   fmt.Sprintf("Range(%v, %v)", min, max),
+		advice: fmt.Sprintf("must be a number from %v to %v", min, max),
+		check: func(v interface{}) bool {
+			f, ok := v.(float64)
+			return ok && f >= min && f <= max
+		},
+	}
+}
+
+// Matches requires a string that matches the regular expression.
+func Matches(re *regexp.Regexp) Constraint {
+	return Constraint{
+		name:   fmt.Sprintf("Matches(%q)", re.String()),
+		advice: fmt.Sprintf("must be a string matching %q", re.String()),
+		check: func(v interface{}) bool {
+			s, ok := v.(string)
+			return ok && re.MatchString(s)
+		},
+	}
+}
+
+// OneOf requires a value equal to one of the given values. Only strings,
+// float64, bool and nil values can be equal.
+func OneOf(values ...interface{}) Constraint {
+	formatted := make([]string, len(values))
+	for i := range values {
+		formatted[i] = fmt.Sprintf("%#v", values[i])
+	}
+	return Constraint{
+		name:   fmt.Sprintf("OneOf(%s)", strings.Join(formatted, ", ")),
+		advice: fmt.Sprintf("must be one of %s", strings.Join(formatted, ", ")),
+		check: func(v interface{}) bool {
+			switch v.(type) {
+			case string, float64, bool, nil:
+			default:
+				return false
+			}
+			for i := range values {
+				if values[i] == v {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func (s *stick) Check(p Pinata, constraints ...Constraint) Pinata {
+	if s.err != nil {
+		return Pinata{}
+	}
+	for _, c := range constraints {
+		if c.check(p.Value()) {
+			continue
+		}
+		c := c
+		s.err = &Error{
+			context: &ErrorContext{
+				methodName: "Check",
+				methodArgs: func() []interface{} { return []interface{}{c} },
+				source:     p.source,
+				next:       p.context,
+			},
+			reason: ErrorReasonConstraint,
+			advice: c.advice,
+		}
+		return Pinata{}
+	}
+	return p
+}
@@ -0,0 +1,48 @@
+package pinata_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestCheck(t *testing.T) {
+	stick, thePinata := start(t)
+
+	name := stick.String(stick.Check(stick.Path(thePinata, "Name"), pinata.NonEmpty(), pinata.MaxLen(10)))
+	if err := stick.ClearError(); err != nil || name != "Kevin" {
+		t.Fatal("Name must satisfy the constraints", err)
+	}
+	stick.Check(stick.Path(thePinata, "Phone"), pinata.MinLen(2))
+	stick.Check(stick.Path(thePinata, "Name"), pinata.OneOf("Kevin", "Bob"))
+	stick.Check(pinata.NewPinata(float64(5)), pinata.Range(0, 100))
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	stick.Check(stick.Path(thePinata, "Name"), pinata.MaxLen(10), pinata.Matches(regexp.MustCompile(`^[a-z]+$`)))
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("Name must not match the regular expression")
+	}
+	if err.(*pinata.Error).Reason() != pinata.ErrorReasonConstraint {
+		t.Error("error reason must be constraint violated")
+	}
+	const expected = `pinata: constraint violated (must be a string matching "^[a-z]+$") at Check(Matches("^[a-z]+$")) at Path("Name")`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	for _, c := range []pinata.Constraint{
+		pinata.NonEmpty(),
+		pinata.MinLen(1),
+		pinata.Range(0, 1),
+		pinata.OneOf("napping"),
+	} {
+		stick.Check(stick.Path(thePinata, "Address", "City"), c)
+		if err := stick.ClearError(); err == nil {
+			t.Errorf("null must violate %#v", c)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package pinata
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions configures ParseCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter, a comma if zero. Use '\t' for TSV.
+	Comma rune
+	// InferTypes turns fields holding a plain decimal number, such as -12.5,
+	// into a float64 and fields holding true or false into a bool. Other
+	// fields remain strings, including numbers with leading zeros such as zip
+	// codes, underscores or exponents.
+	InferTypes bool
+}
+
+// ParseCSV reads CSV data whose first record is a header and returns a Pinata
+// holding a []interface{} with a map[string]interface{} for every other
+// record, keyed by the header. The source of each row is its record number
+// counting the header as row 1, like a spreadsheet does, such as "row 2" for
+// the first row after the header, and the source of each
+// field adds its column, such as "row 2, column price", so errors name them.
+//
+// The error is a *Error if the data is malformed, a record has the wrong
+// number of fields or the header holds a column twice.
+func ParseCSV(r io.Reader, opts CSVOptions) (Pinata, error) {
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return NewPinata([]interface{}{}), nil
+	}
+	if err != nil {
+		return Pinata{}, csvError(err, 1)
+	}
+	header = append([]string(nil), header...)
+	columns := make(map[string]bool, len(header))
+	for _, column := range header {
+		if columns[column] {
+			return Pinata{}, csvError(fmt.Errorf("column %q occurs twice in the header", column), 1)
+		}
+		columns[column] = true
+	}
+
+	rows := []interface{}{}
+	var rowSources []string
+	sources := make(map[slot]string)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		// the header is row 1, so the first record after it is row 2
+		row := len(rows) + 2
+		if err != nil {
+			return Pinata{}, csvError(err, row)
+		}
+		rowSource := "row " + strconv.Itoa(row)
+		fields := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			var value interface{} = record[i]
+			if opts.InferTypes {
+				value = inferCSVType(record[i])
+			}
+			fields[column] = value
+			sources[slotOf(fields, column)] = rowSource + ", column " + column
+		}
+		rows = append(rows, fields)
+		rowSources = append(rowSources, rowSource)
+	}
+	// the slice is complete so its slots no longer change
+	for i, rowSource := range rowSources {
+		sources[slotOf(rows, strconv.Itoa(i))] = rowSource
+	}
+
+	p := NewPinata(rows)
+	p.sources = sources
+	return p, nil
+}
+
+func inferCSVType(field string) interface{} {
+	switch field {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if !plainDecimal.MatchString(field) {
+		return field
+	}
+	f, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return field
+	}
+	return f
+}
+
+// plainDecimal matches numbers without leading zeros, underscores or
+// exponents, so identifiers such as zip codes are not read as numbers.
+var plainDecimal = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+func csvError(err error, row int) *Error {
+	advice := err.Error()
+	if parseErr, ok := err.(*csv.ParseError); ok {
+		advice = parseErr.Err.Error()
+	}
+	source := "row " + strconv.Itoa(row)
+	return &Error{
+		context: &ErrorContext{
+			methodName: "ParseCSV",
+			methodArgs: func() []interface{} { return nil },
+			source:     source,
+		},
+		reason: ErrorReasonInvalidInput,
+		advice: advice,
+	}
+}
+
+// CSVWriteOptions configures WriteCSVWith.
+type CSVWriteOptions struct {
+	// Comma is the field delimiter, a comma if zero. Use '\t' for TSV.
+	Comma rune
+	// Missing is written for a path that does not exist within a row.
+	Missing string
+	// Null is written for a nil value.
+	Null string
+}
+
+// WriteCSV is WriteCSVWith using the default options.
+func WriteCSV(w io.Writer, rows Pinata, columns ...Path) ([]*Error, error) {
+	return WriteCSVWith(CSVWriteOptions{}, w, rows, columns...)
+}
+
+// WriteCSVWith writes the slice held by rows as CSV, starting with a header
+// that joins the keys of each column path with dots. Every element becomes a
+// record with a field for each column, read from the element with
+// Stick.Path. Strings are written as they are, integers and json.Number
+// values exactly, floats in their shortest form and bools as true or false.
+//
+// A column that cannot be written, because the element is not a map or the
+// value is a map or a slice, is written as missing and its error is
+// collected. The record is written regardless, so one bad row does not stop
+// an export. The error is only non-nil if rows does not hold a slice or
+// writing fails.
+func WriteCSVWith(opts CSVWriteOptions, w io.Writer, rows Pinata, columns ...Path) ([]*Error, error) {
+	writer := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		writer.Comma = opts.Comma
+	}
+
+	elements, ok := rows.Slice()
+	if !ok {
+		return nil, &Error{
+			context: &ErrorContext{
+				methodName: "WriteCSV",
+				methodArgs: func() []interface{} { return pathsToInterfaceSlice(columns) },
+				source:     rows.source,
+				next:       rows.context,
+			},
+			reason: ErrorReasonIncompatibleType,
+			advice: "rows must be a slice",
+		}
+	}
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.Join(column, ".")
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	var errs []*Error
+	stick := NewStick()
+	record := make([]string, len(columns))
+	for i := range elements {
+		row := stick.Index(rows, i)
+		for j, column := range columns {
+			value := row
+			if len(column) > 0 {
+				value = stick.Path(row, column...)
+			}
+			err := stick.ClearError()
+			if err == nil {
+				var cell string
+				cell, err = csvField(value, column, opts)
+				record[j] = cell
+			}
+			if err == nil {
+				continue
+			}
+			record[j] = opts.Missing
+			if err, ok := err.(*Error); ok && err.Reason() != ErrorReasonNotFound {
+				errs = append(errs, err)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return errs, err
+		}
+	}
+	writer.Flush()
+	return errs, writer.Error()
+}
+
+// csvField formats a value for a CSV field.
+func csvField(p Pinata, column Path, opts CSVWriteOptions) (string, error) {
+	switch v := p.Value().(type) {
+	case nil:
+		return opts.Null, nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case json.Number:
+		// written as decoded so large integers such as IDs stay intact
+		return v.String(), nil
+	}
+	if i, ok := integer(p.Value()); ok {
+		return strconv.FormatInt(i, 10), nil
+	}
+	if u, ok := unsignedInteger(p.Value()); ok {
+		return strconv.FormatUint(u, 10), nil
+	}
+	return "", &Error{
+		context: &ErrorContext{
+			methodName: "WriteCSV",
+			methodArgs: func() []interface{} { return []interface{}{column} },
+			source:     p.source,
+			next:       p.context,
+		},
+		reason: ErrorReasonIncompatibleType,
+		advice: fmt.Sprintf("%s cannot be written to a field", schemaType(p.Value())),
+	}
+}
@@ -0,0 +1,142 @@
+package pinata_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestParseCSV(t *testing.T) {
+	const data = "sku,price,active\nA1,12.5,true\nB2,n/a,false\n"
+
+	p, err := pinata.ParseCSV(strings.NewReader(data), pinata.CSVOptions{InferTypes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decode(t, `[
+		{"sku": "A1", "price": 12.5, "active": true},
+		{"sku": "B2", "price": "n/a", "active": false}
+	]`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), p.Value())
+	}
+
+	stick := pinata.NewStick()
+	stick.PathFloat64(stick.Index(p, 1), "price")
+	err = stick.ClearError()
+	if err == nil {
+		t.Fatal("price must not be a number")
+	}
+	if source := err.(*pinata.Error).Source(); source != "row 3, column price" {
+		t.Errorf("error must name row 3, column price, got %q", source)
+	}
+
+	stick.PathString(stick.Index(p, 0), "missing")
+	err = stick.ClearError()
+	if source := err.(*pinata.Error).Source(); source != "row 2" {
+		t.Errorf("error must name row 2, got %q", source)
+	}
+
+	p, err = pinata.ParseCSV(strings.NewReader("sku\tprice\nA1\t12.5\n"), pinata.CSVOptions{Comma: '\t'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = decode(t, `[{"sku": "A1", "price": "12.5"}]`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), p.Value())
+	}
+}
+
+func TestParseCSVRows(t *testing.T) {
+	const data = "sku,note,zip,qty\nA1,\"two\nlines\",00123,1_000\nB2,plain,0,-1.5\n"
+
+	p, err := pinata.ParseCSV(strings.NewReader(data), pinata.CSVOptions{InferTypes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decode(t, `[
+		{"sku": "A1", "note": "two\nlines", "zip": "00123", "qty": "1_000"},
+		{"sku": "B2", "note": "plain", "zip": 0, "qty": -1.5}
+	]`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), p.Value())
+	}
+
+	stick := pinata.NewStick()
+	stick.PathBool(stick.Index(p, 1), "sku")
+	err = stick.ClearError()
+	if err == nil {
+		t.Fatal("sku must not be a bool")
+	}
+	if source := err.(*pinata.Error).Source(); source != "row 3, column sku" {
+		t.Errorf("a multi-line field must not shift the row, got %q", source)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	for _, data := range []string{
+		"a,a\n1,2\n",
+		"a,b\n1,2\n3\n",
+		"a\n\"unterminated\n",
+	} {
+		_, err := pinata.ParseCSV(strings.NewReader(data), pinata.CSVOptions{})
+		if err == nil {
+			t.Errorf("%q must result in an error", data)
+		} else if _, ok := err.(*pinata.Error); !ok {
+			t.Errorf("%q must result in a *pinata.Error, got %T", data, err)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := decode(t, `[
+		{"name": "Alice", "age": 30, "address": {"city": "Ghent"}, "vip": true},
+		{"name": "Bob", "age": null, "address": {}},
+		{"name": "Carol", "age": 41, "address": {"city": ["a", "b"]}},
+		"broken"
+	]`)
+
+	var b strings.Builder
+	errs, err := pinata.WriteCSVWith(pinata.CSVWriteOptions{Missing: "-", Null: "NULL"}, &b, rows,
+		pinata.P("name"), pinata.P("age"), pinata.P("address", "city"), pinata.P("vip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = "name,age,address.city,vip\n" +
+		"Alice,30,Ghent,true\n" +
+		"Bob,NULL,-,-\n" +
+		"Carol,41,-,-\n" +
+		"-,-,-,-\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+	if len(errs) != 5 {
+		t.Fatalf("expected 5 errors, got %v", errs)
+	}
+	const expectedErr = `pinata: incompatible type (array cannot be written to a field) at WriteCSV(P("address", "city"))`
+	if !strings.HasPrefix(errs[0].Error(), expectedErr) {
+		t.Errorf("expected %q, got %q", expectedErr, errs[0].Error())
+	}
+
+	if _, err := pinata.WriteCSV(&b, pinata.NewPinata("rows")); err == nil {
+		t.Error("rows must be a slice")
+	}
+}
+
+func TestWriteCSVLargeNumbers(t *testing.T) {
+	rows := pinata.NewPinata([]interface{}{
+		map[string]interface{}{"id": json.Number("12345678901234567891"), "n": int64(9007199254740993)},
+		map[string]interface{}{"id": uint64(18446744073709551615), "n": float32(0.1)},
+	})
+	var b strings.Builder
+	errs, err := pinata.WriteCSV(&b, rows, pinata.P("id"), pinata.P("n"))
+	if err != nil || len(errs) > 0 {
+		t.Fatal(err, errs)
+	}
+	const expected = "id,n\n12345678901234567891,9007199254740993\n18446744073709551615,0.1\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
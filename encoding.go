@@ -0,0 +1,360 @@
+package pinata
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+)
+
+// UUID is a universally unique identifier as defined by RFC 9562.
+type UUID [16]byte
+
+// String returns the UUID in its canonical textual form.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Each parse function returns the parsed value or advice on why it failed.
+
+func parseBytes(str string) (interface{}, string) {
+	for _, encoding := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	} {
+		if b, err := encoding.DecodeString(str); err == nil {
+			return b, ""
+		}
+	}
+	return nil, fmt.Sprintf("%q is not base64 encoded", str)
+}
+
+// parseURL accepts absolute URLs only. A URL with an authority, such as
+// https://example.com, must name a host unless it is a file URL.
+func parseURL(str string) (interface{}, string) {
+	if str == "" {
+		return nil, "an empty string is not a URL"
+	}
+	u, err := url.Parse(str)
+	if err != nil {
+		return nil, fmt.Sprintf("%q is not a URL: %s", str, err.(*url.Error).Err)
+	}
+	if u.Scheme == "" {
+		return nil, fmt.Sprintf("%q is not an absolute URL such as \"https://example.com\"", str)
+	}
+	if u.Opaque == "" && u.Host == "" && u.Scheme != "file" {
+		return nil, fmt.Sprintf("%q is a URL without a host", str)
+	}
+	return u, ""
+}
+
+func parseUUID(str string) (interface{}, string) {
+	var u UUID
+	advice := fmt.Sprintf(`%q is not a UUID such as "123e4567-e89b-12d3-a456-426614174000"`, str)
+	if len(str) != 36 || str[8] != '-' || str[13] != '-' || str[18] != '-' || str[23] != '-' {
+		return nil, advice
+	}
+	src := []byte(str[0:8] + str[9:13] + str[14:18] + str[19:23] + str[24:])
+	if _, err := hex.Decode(u[:], src); err != nil {
+		return nil, advice
+	}
+	return u, ""
+}
+
+func parseIP(str string) (interface{}, string) {
+	ip, err := netip.ParseAddr(str)
+	if err != nil {
+		return nil, fmt.Sprintf("%q is not an IP address", str)
+	}
+	return ip, ""
+}
+
+func parsePrefix(str string) (interface{}, string) {
+	prefix, err := netip.ParsePrefix(str)
+	if err != nil {
+		return nil, fmt.Sprintf(`%q is not an IP prefix such as "192.168.0.0/16"`, str)
+	}
+	return prefix, ""
+}
+
+func parseEmail(str string) (interface{}, string) {
+	address, err := mail.ParseAddress(str)
+	if err != nil {
+		return nil, fmt.Sprintf("%q is not an email address: %s", str, err)
+	}
+	return address, ""
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalParse(p Pinata, methodName string, input func() []interface{}, parse func(string) (interface{}, string)) (interface{}, bool) {
+	str := s.internalString(p, methodName, input)
+	if s.err != nil {
+		return nil, false
+	}
+	v, advice := parse(str)
+	if advice != "" {
+		s.invalidFormat(p, methodName, input, advice)
+		return nil, false
+	}
+	return v, true
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalBytes(p Pinata, methodName string, input func() []interface{}) []byte {
+	if v, ok := s.internalParse(p, methodName, input, parseBytes); ok {
+		return v.([]byte)
+	}
+	return nil
+}
+
+func (s *stick) Bytes(p Pinata) []byte {
+	if s.err != nil {
+		return nil
+	}
+	return s.internalBytes(p, "Bytes", func() []interface{} { return nil })
+}
+
+func (s *stick) IndexBytes(p Pinata, index int) []byte {
+	if s.err != nil {
+		return nil
+	}
+	const methodName = "IndexBytes"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return nil
+	}
+	pinata.context = p.context
+	return s.internalBytes(pinata, methodName, func() []interface{} { return []interface{}{index} })
+}
+
+func (s *stick) PathBytes(p Pinata, path ...string) []byte {
+	if s.err != nil {
+		return nil
+	}
+	const methodName = "PathBytes"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return nil
+	}
+	pinata.context = p.context
+	return s.internalBytes(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalURL(p Pinata, methodName string, input func() []interface{}) *url.URL {
+	if v, ok := s.internalParse(p, methodName, input, parseURL); ok {
+		return v.(*url.URL)
+	}
+	return nil
+}
+
+func (s *stick) URL(p Pinata) *url.URL {
+	if s.err != nil {
+		return nil
+	}
+	return s.internalURL(p, "URL", func() []interface{} { return nil })
+}
+
+func (s *stick) IndexURL(p Pinata, index int) *url.URL {
+	if s.err != nil {
+		return nil
+	}
+	const methodName = "IndexURL"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return nil
+	}
+	pinata.context = p.context
+	return s.internalURL(pinata, methodName, func() []interface{} { return []interface{}{index} })
+}
+
+func (s *stick) PathURL(p Pinata, path ...string) *url.URL {
+	if s.err != nil {
+		return nil
+	}
+	const methodName = "PathURL"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return nil
+	}
+	pinata.context = p.context
+	return s.internalURL(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalUUID(p Pinata, methodName string, input func() []interface{}) UUID {
+	if v, ok := s.internalParse(p, methodName, input, parseUUID); ok {
+		return v.(UUID)
+	}
+	return UUID{}
+}
+
+func (s *stick) UUID(p Pinata) UUID {
+	if s.err != nil {
+		return UUID{}
+	}
+	return s.internalUUID(p, "UUID", func() []interface{} { return nil })
+}
+
+func (s *stick) IndexUUID(p Pinata, index int) UUID {
+	if s.err != nil {
+		return UUID{}
+	}
+	const methodName = "IndexUUID"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return UUID{}
+	}
+	pinata.context = p.context
+	return s.internalUUID(pinata, methodName, func() []interface{} { return []interface{}{index} })
+}
+
+func (s *stick) PathUUID(p Pinata, path ...string) UUID {
+	if s.err != nil {
+		return UUID{}
+	}
+	const methodName = "PathUUID"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return UUID{}
+	}
+	pinata.context = p.context
+	return s.internalUUID(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalIP(p Pinata, methodName string, input func() []interface{}) netip.Addr {
+	if v, ok := s.internalParse(p, methodName, input, parseIP); ok {
+		return v.(netip.Addr)
+	}
+	return netip.Addr{}
+}
+
+func (s *stick) IP(p Pinata) netip.Addr {
+	if s.err != nil {
+		return netip.Addr{}
+	}
+	return s.internalIP(p, "IP", func() []interface{} { return nil })
+}
+
+func (s *stick) IndexIP(p Pinata, index int) netip.Addr {
+	if s.err != nil {
+		return netip.Addr{}
+	}
+	const methodName = "IndexIP"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return netip.Addr{}
+	}
+	pinata.context = p.context
+	return s.internalIP(pinata, methodName, func() []interface{} { return []interface{}{index} })
+}
+
+func (s *stick) PathIP(p Pinata, path ...string) netip.Addr {
+	if s.err != nil {
+		return netip.Addr{}
+	}
+	const methodName = "PathIP"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return netip.Addr{}
+	}
+	pinata.context = p.context
+	return s.internalIP(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalPrefix(p Pinata, methodName string, input func() []interface{}) netip.Prefix {
+	if v, ok := s.internalParse(p, methodName, input, parsePrefix); ok {
+		return v.(netip.Prefix)
+	}
+	return netip.Prefix{}
+}
+
+func (s *stick) Prefix(p Pinata) netip.Prefix {
+	if s.err != nil {
+		return netip.Prefix{}
+	}
+	return s.internalPrefix(p, "Prefix", func() []interface{} { return nil })
+}
+
+func (s *stick) IndexPrefix(p Pinata, index int) netip.Prefix {
+	if s.err != nil {
+		return netip.Prefix{}
+	}
+	const methodName = "IndexPrefix"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return netip.Prefix{}
+	}
+	pinata.context = p.context
+	return s.internalPrefix(pinata, methodName, func() []interface{} { return []interface{}{index} })
+}
+
+func (s *stick) PathPrefix(p Pinata, path ...string) netip.Prefix {
+	if s.err != nil {
+		return netip.Prefix{}
+	}
+	const methodName = "PathPrefix"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return netip.Prefix{}
+	}
+	pinata.context = p.context
+	return s.internalPrefix(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalEmail(p Pinata, methodName string, input func() []interface{}) *mail.Address {
+	if v, ok := s.internalParse(p, methodName, input, parseEmail); ok {
+		return v.(*mail.Address)
+	}
+	return nil
+}
+
+func (s *stick) Email(p Pinata) *mail.Address {
+	if s.err != nil {
+		return nil
+	}
+	return s.internalEmail(p, "Email", func() []interface{} { return nil })
+}
+
+func (s *stick) IndexEmail(p Pinata, index int) *mail.Address {
+	if s.err != nil {
+		return nil
+	}
+	const methodName = "IndexEmail"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return nil
+	}
+	pinata.context = p.context
+	return s.internalEmail(pinata, methodName, func() []interface{} { return []interface{}{index} })
+}
+
+func (s *stick) PathEmail(p Pinata, path ...string) *mail.Address {
+	if s.err != nil {
+		return nil
+	}
+	const methodName = "PathEmail"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return nil
+	}
+	pinata.context = p.context
+	return s.internalEmail(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
+}
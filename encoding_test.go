@@ -0,0 +1,89 @@
+package pinata_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestEncodedStrings(t *testing.T) {
+	stick := pinata.NewStick()
+	p := pinata.NewPinata(map[string]interface{}{
+		"Avatar":  "R29waGVy",
+		"Token":   "_-8",
+		"Website": "https://example.com/kevin?lang=en",
+		"ID":      "123E4567-E89B-12D3-A456-426614174000",
+		"Hosts":   []interface{}{"192.168.0.1", "::1"},
+		"Network": "10.0.0.0/8",
+		"Email":   "Kevin <kevin@example.com>",
+		"Broken":  "://example.com",
+		"Links": []interface{}{
+			"mailto:kevin@example.com", "file:///tmp/kevin", "not a url", "/relative", "http:/nohost",
+		},
+	})
+
+	if v := stick.PathBytes(p, "Avatar"); !bytes.Equal(v, []byte("Gopher")) {
+		t.Error("Avatar must be decoded, got", v)
+	}
+	if v := stick.PathBytes(p, "Token"); !bytes.Equal(v, []byte{0xff, 0xef}) {
+		t.Error("Token must be decoded as unpadded base64url, got", v)
+	}
+	if v := stick.PathURL(p, "Website"); v == nil || v.Host != "example.com" || v.Query().Get("lang") != "en" {
+		t.Error("Website must be parsed, got", v)
+	}
+	if v := stick.PathUUID(p, "ID"); v.String() != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Error("ID must be parsed, got", v)
+	}
+	if v := stick.IndexIP(stick.Path(p, "Hosts"), 1); !v.IsLoopback() {
+		t.Error("Hosts index 1 must be the loopback address, got", v)
+	}
+	if v := stick.PathPrefix(p, "Network"); v.Bits() != 8 {
+		t.Error("Network must be parsed, got", v)
+	}
+	if v := stick.PathEmail(p, "Email"); v == nil || v.Address != "kevin@example.com" || v.Name != "Kevin" {
+		t.Error("Email must be parsed, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, f := range map[string]func(){
+		"Bytes":  func() { stick.PathBytes(p, "Website") },
+		"URL":    func() { stick.PathURL(p, "Broken") },
+		"UUID":   func() { stick.PathUUID(p, "Avatar") },
+		"IP":     func() { stick.PathIP(p, "Network") },
+		"Prefix": func() { stick.IndexPrefix(stick.Path(p, "Hosts"), 0) },
+		"Email":  func() { stick.PathEmail(p, "Website") },
+	} {
+		f()
+		err := stick.ClearError()
+		if err == nil {
+			t.Errorf("%s: invalid input must result in an error", name)
+			continue
+		}
+		t.Log(err)
+	}
+
+	links := stick.Path(p, "Links")
+	for i, valid := range []bool{true, true, false, false, false} {
+		stick.IndexURL(links, i)
+		err := stick.ClearError()
+		if valid && err != nil {
+			t.Errorf("Links index %d must be a URL: %v", i, err)
+		}
+		if !valid && (err == nil || err.(*pinata.Error).Reason() != pinata.ErrorReasonInvalidFormat) {
+			t.Errorf("Links index %d must be an invalid format, got %v", i, err)
+		}
+	}
+
+	stick.PathUUID(p, "Avatar")
+	err := stick.ClearError()
+	if err.(*pinata.Error).Reason() != pinata.ErrorReasonInvalidFormat {
+		t.Error("error reason must be invalid format")
+	}
+	const expected = `pinata: invalid format ("R29waGVy" is not a UUID such as "123e4567-e89b-12d3-a456-426614174000") at PathUUID("Avatar")`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,160 @@
+package pinata
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FromEnv is FromEnviron applied to the environment of the current process.
+func FromEnv(prefix, separator string) (Pinata, error) {
+	return FromEnviron(os.Environ(), prefix, separator)
+}
+
+// FromEnviron builds a Pinata from "KEY=value" entries, as returned by
+// os.Environ or ReadDotEnv. Only keys starting with the prefix are used. The
+// rest of the key is split on the separator into a path of lower case map
+// keys, so with prefix "APP_" and separator "__" the entry APP_DB__HOST=x
+// becomes {"db": {"host": "x"}}. An empty separator disables nesting, so
+// every variable becomes a key of the root map.
+//
+// All values are strings, create the Stick with WithCoercion to read numbers
+// and bools. The source of each value is the name of its variable so errors
+// name it, while the Pinata itself has the source "env".
+//
+// The error is a *Error if two variables conflict, for example APP_DB=x and
+// APP_DB__HOST=y.
+func FromEnviron(environ []string, prefix, separator string) (Pinata, error) {
+	sorted := make([]string, len(environ))
+	copy(sorted, environ)
+	sort.Strings(sorted)
+
+	root := make(map[string]interface{})
+	sources := make(map[slot]string)
+	// variables records the variable that created each map or value
+	variables := make(map[slot]string)
+
+	fail := func(name, advice string) (Pinata, error) {
+		return Pinata{}, &Error{
+			context: &ErrorContext{
+				methodName: "FromEnviron",
+				methodArgs: func() []interface{} { return []interface{}{prefix, separator} },
+				source:     name,
+			},
+			reason: ErrorReasonInvalidInput,
+			advice: advice,
+		}
+	}
+
+	for _, entry := range sorted {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || name == prefix {
+			continue
+		}
+		path := []string{strings.ToLower(strings.TrimPrefix(name, prefix))}
+		if separator != "" {
+			path = strings.Split(path[0], separator)
+		}
+		for _, key := range path {
+			if key == "" {
+				return fail(name, fmt.Sprintf("%s holds an empty key", name))
+			}
+		}
+
+		contents := root
+		for _, key := range path[:len(path)-1] {
+			switch v := contents[key].(type) {
+			case nil:
+				child := make(map[string]interface{})
+				contents[key] = child
+				variables[slotOf(contents, key)] = name
+				contents = child
+			case map[string]interface{}:
+				contents = v
+			default:
+				return fail(name, fmt.Sprintf("%s conflicts with %s", name, variables[slotOf(contents, key)]))
+			}
+		}
+		last := path[len(path)-1]
+		if _, ok := contents[last]; ok {
+			return fail(name, fmt.Sprintf("%s conflicts with %s", name, variables[slotOf(contents, last)]))
+		}
+		contents[last] = value
+		variables[slotOf(contents, last)] = name
+		sources[slotOf(contents, last)] = name
+	}
+
+	p := NewPinataFromSource("env", root)
+	p.sources = sources
+	return p, nil
+}
+
+// ReadDotEnv reads a .env file and returns its "KEY=value" entries, which can
+// be passed to FromEnviron. Blank lines and lines starting with # are
+// skipped and an "export " prefix is ignored. Values may be enclosed in
+// single quotes, taken literally, or double quotes, which support the \n, \",
+// \\ and \$ escapes. Unquoted values end at a " #" comment.
+func ReadDotEnv(r io.Reader) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+		name, value, ok := strings.Cut(text, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("pinata: line %d: expected KEY=value", line)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, `'`):
+			end := strings.Index(value[1:], `'`)
+			if end < 0 {
+				return nil, fmt.Errorf("pinata: line %d: unterminated single quote", line)
+			}
+			value = value[1 : end+1]
+		case strings.HasPrefix(value, `"`):
+			var b strings.Builder
+			closed := false
+			for i := 1; i < len(value); i++ {
+				c := value[i]
+				if c == '"' {
+					closed = true
+					break
+				}
+				if c == '\\' && i+1 < len(value) {
+					i++
+					switch value[i] {
+					case 'n':
+						c = '\n'
+					case '"', '\\', '$':
+						c = value[i]
+					default:
+						_ = b.WriteByte('\\')
+						c = value[i]
+					}
+				}
+				_ = b.WriteByte(c)
+			}
+			if !closed {
+				return nil, fmt.Errorf("pinata: line %d: unterminated double quote", line)
+			}
+			value = b.String()
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		entries = append(entries, name+"="+value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,84 @@
+package pinata_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestFromEnviron(t *testing.T) {
+	p, err := pinata.FromEnviron([]string{
+		"APP_DB__HOST=db.internal",
+		"APP_DB__PORT=five",
+		"APP_DEBUG=1",
+		"APP_MAX_CONNS=10",
+		"HOME=/root",
+	}, "APP_", "__")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decode(t, `{"db": {"host": "db.internal", "port": "five"}, "debug": "1", "max_conns": "10"}`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), p.Value())
+	}
+
+	stick := pinata.NewStick(pinata.WithCoercion(pinata.CoerceFloat64 | pinata.CoerceBool))
+	if !stick.PathBool(p, "debug") || stick.PathFloat64(p, "max_conns") != 10 {
+		t.Error("values must be coerced")
+	}
+	stick.PathFloat64(p, "db", "port")
+	err = stick.ClearError()
+	if err == nil {
+		t.Fatal("port must not be a number")
+	}
+	const expectedErr = `pinata: incompatible type ("five" is not a number) at PathFloat64("db", "port") (from "APP_DB__PORT")`
+	if err.Error() != expectedErr {
+		t.Errorf("expected %q, got %q", expectedErr, err.Error())
+	}
+
+	_, err = pinata.FromEnviron([]string{"APP_DB__HOST=x", "APP_DB=y"}, "APP_", "__")
+	if err == nil {
+		t.Fatal("conflicting variables must result in an error")
+	}
+	if source := err.(*pinata.Error).Source(); source != "APP_DB__HOST" {
+		t.Errorf("error must name APP_DB__HOST, got %q", source)
+	}
+	t.Log(err)
+
+	p, err = pinata.FromEnviron([]string{"APP_DB__HOST=x", "APP_DB=y"}, "APP_", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = decode(t, `{"db__host": "x", "db": "y"}`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("an empty separator must not nest, expected %v, got %v", expected.Value(), p.Value())
+	}
+}
+
+func TestReadDotEnv(t *testing.T) {
+	entries, err := pinata.ReadDotEnv(strings.NewReader(`
+# database
+APP_DB__HOST=db.internal # the primary
+export APP_DB__USER='admin #1'
+APP_GREETING="hello \"gopher\"\nbye"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"APP_DB__HOST=db.internal",
+		"APP_DB__USER=admin #1",
+		"APP_GREETING=hello \"gopher\"\nbye",
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected %q, got %q", expected, entries)
+	}
+
+	if _, err := pinata.ReadDotEnv(strings.NewReader("A=1\nB='2\n")); err == nil {
+		t.Error("an unterminated quote must result in an error")
+	} else {
+		t.Log(err)
+	}
+}
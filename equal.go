@@ -0,0 +1,241 @@
+package pinata
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Equal reports whether the Pinatas hold deeply equal values. Numbers are
+// compared by value whatever their type, so float64(1) equals int(1).
+func Equal(a, b Pinata) bool {
+	return equalValues(a.Value(), b.Value())
+}
+
+// ChangeKind describes how a value differs between two Pinatas.
+type ChangeKind string
+
+const (
+	// ChangeAdded indicates a value only exists in the to Pinata.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved indicates a value only exists in the from Pinata.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeChanged indicates a value of the same type holds something else.
+	ChangeChanged ChangeKind = "changed"
+	// ChangeTypeChanged indicates a value changed type, for example from a
+	// string to a map.
+	ChangeTypeChanged ChangeKind = "type-changed"
+)
+
+// Change is a single difference found by Diff.
+type Change struct {
+	// Pointer is the RFC 6901 JSON Pointer of the value that changed.
+	Pointer string
+	Kind    ChangeKind
+	// Old is the value in the from Pinata, nil if it was added.
+	Old interface{}
+	// New is the value in the to Pinata, nil if it was removed.
+	New interface{}
+}
+
+// Diff returns the changes needed to turn the from Pinata into the to Pinata,
+// ordered by location with map keys sorted. Slices are compared index by
+// index.
+func Diff(from, to Pinata) []Change {
+	return diff(from.Value(), to.Value(), nil, nil)
+}
+
+func diff(from, to interface{}, location []string, changes []Change) []Change {
+	if schemaType(from) != schemaType(to) {
+		return append(changes, Change{Pointer: formatPointer(location), Kind: ChangeTypeChanged, Old: from, New: to})
+	}
+	switch o := from.(type) {
+	case map[string]interface{}:
+		n := to.(map[string]interface{})
+		keys := make([]string, 0, len(o)+len(n))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		for k := range n {
+			if _, ok := o[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ov, inOld := o[k]
+			nv, inNew := n[k]
+			switch {
+			case !inNew:
+				changes = append(changes, Change{Pointer: formatPointer(appendToken(location, k)), Kind: ChangeRemoved, Old: ov})
+			case !inOld:
+				changes = append(changes, Change{Pointer: formatPointer(appendToken(location, k)), Kind: ChangeAdded, New: nv})
+			default:
+				changes = diff(ov, nv, appendToken(location, k), changes)
+			}
+		}
+	case []interface{}:
+		n := to.([]interface{})
+		for i := 0; i < len(o) || i < len(n); i++ {
+			token := strconv.Itoa(i)
+			switch {
+			case i >= len(n):
+				changes = append(changes, Change{Pointer: formatPointer(appendToken(location, token)), Kind: ChangeRemoved, Old: o[i]})
+			case i >= len(o):
+				changes = append(changes, Change{Pointer: formatPointer(appendToken(location, token)), Kind: ChangeAdded, New: n[i]})
+			default:
+				changes = diff(o[i], n[i], appendToken(location, token), changes)
+			}
+		}
+	default:
+		if !equalValues(from, to) {
+			changes = append(changes, Change{Pointer: formatPointer(location), Kind: ChangeChanged, Old: from, New: to})
+		}
+	}
+	return changes
+}
+
+// number returns v as a float64 if it is one of the numeric types produced by
+// the decoders Pinata is used with (the bool indicates success).
+func number(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int8:
+		return float64(t), true
+	case int16:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case uint:
+		return float64(t), true
+	case uint8:
+		return float64(t), true
+	case uint16:
+		return float64(t), true
+	case uint32:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// integer returns v as an int64 if it is one of the signed integer types (the
+// bool indicates success).
+func integer(v interface{}) (int64, bool) {
+	switch t := v.(type) {
+	case int:
+		return int64(t), true
+	case int8:
+		return int64(t), true
+	case int16:
+		return int64(t), true
+	case int32:
+		return int64(t), true
+	case int64:
+		return t, true
+	}
+	return 0, false
+}
+
+// unsignedInteger returns v as a uint64 if it is one of the unsigned integer
+// types (the bool indicates success).
+func unsignedInteger(v interface{}) (uint64, bool) {
+	switch t := v.(type) {
+	case uint:
+		return uint64(t), true
+	case uint8:
+		return uint64(t), true
+	case uint16:
+		return uint64(t), true
+	case uint32:
+		return uint64(t), true
+	case uint64:
+		return t, true
+	}
+	return 0, false
+}
+
+// bigInteger returns v as a big.Int if it is an integer type, an integral
+// json.Number or an integral float (the bool indicates success).
+func bigInteger(v interface{}) (*big.Int, bool) {
+	if s, ok := exactInteger(v); ok {
+		return new(big.Int).SetString(s, 10)
+	}
+	f, ok := number(v)
+	if !ok || f != math.Trunc(f) || math.IsInf(f, 0) {
+		return nil, false
+	}
+	i, _ := big.NewFloat(f).Int(nil)
+	return i, true
+}
+
+// equalNumbers compares two numbers, exactly if both are integers so large
+// IDs that round to the same float64 differ.
+func equalNumbers(a, b interface{}) bool {
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			return x == y
+		}
+	}
+	if x, ok := bigInteger(a); ok {
+		if y, ok := bigInteger(b); ok {
+			return x.Cmp(y) == 0
+		}
+	}
+	x, _ := number(a)
+	y, _ := number(b)
+	return x == y
+}
+
+// equalValues compares two values deeply, treating numbers of different types
+// with the same value as equal. Integers are compared exactly. Values of
+// other types are compared with reflect.DeepEqual.
+func equalValues(a, b interface{}) bool {
+	if _, ok := number(a); ok {
+		_, ok := number(b)
+		return ok && equalNumbers(a, b)
+	}
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equalValues(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case string, bool, nil:
+		return a == b
+	}
+	// other values, such as a time.Time decoded from YAML or TOML
+	return reflect.DeepEqual(a, b)
+}
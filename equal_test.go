@@ -0,0 +1,81 @@
+package pinata_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestEqual(t *testing.T) {
+	a := decode(t, `{"Name": "Kevin", "Age": 3, "Phone": ["+44"], "City": null}`)
+	b := pinata.NewPinata(map[string]interface{}{
+		"Name":  "Kevin",
+		"Age":   3,
+		"Phone": []interface{}{"+44"},
+		"City":  nil,
+	})
+	if !pinata.Equal(a, b) {
+		t.Error("numbers of different types must be equal")
+	}
+	if pinata.Equal(a, decode(t, `{"Name": "Kevin", "Age": 3, "Phone": [], "City": null}`)) {
+		t.Error("different slices must not be equal")
+	}
+	if pinata.Equal(pinata.NewPinata("1"), pinata.NewPinata(float64(1))) {
+		t.Error("a string must not equal a number")
+	}
+
+	for _, pair := range [][2]interface{}{
+		{int64(9007199254740993), int64(9007199254740992)},
+		{json.Number("9007199254740993"), float64(9007199254740992)},
+		{uint64(18446744073709551615), uint64(18446744073709551614)},
+	} {
+		if pinata.Equal(pinata.NewPinata(pair[0]), pinata.NewPinata(pair[1])) {
+			t.Errorf("%v and %v must not be equal", pair[0], pair[1])
+		}
+	}
+	if !pinata.Equal(pinata.NewPinata(int64(1<<60)), pinata.NewPinata(float64(1<<60))) {
+		t.Error("an integer and a float holding the same value must be equal")
+	}
+
+	created := time.Date(2016, 3, 1, 12, 30, 0, 0, time.UTC)
+	c := pinata.NewPinata(map[string]interface{}{"Created": created})
+	if !pinata.Equal(c, c) {
+		t.Error("a document holding a time.Time must equal itself")
+	}
+	if changes := pinata.Diff(c, c); len(changes) != 0 {
+		t.Error("a document holding a time.Time must not differ from itself, got", changes)
+	}
+	if pinata.Equal(c, pinata.NewPinata(map[string]interface{}{"Created": created.Add(time.Second)})) {
+		t.Error("different times must not be equal")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := decode(t, `{"Name": "Kevin", "Age": 3, "Phone": ["+44", "+32"], "Address": {"City": "Gophertown"}, "Tags": "a"}`)
+	to := decode(t, `{"Name": "Kevin", "Age": 4, "Phone": ["+44"], "Address": {"City": "Gophertown", "Zip": "G0"}, "Tags": ["a"]}`)
+
+	expected := []pinata.Change{
+		{Pointer: "/Address/Zip", Kind: pinata.ChangeAdded, New: "G0"},
+		{Pointer: "/Age", Kind: pinata.ChangeChanged, Old: float64(3), New: float64(4)},
+		{Pointer: "/Phone/1", Kind: pinata.ChangeRemoved, Old: "+32"},
+		{Pointer: "/Tags", Kind: pinata.ChangeTypeChanged, Old: "a", New: []interface{}{"a"}},
+	}
+	if changes := pinata.Diff(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %v, got %v", expected, changes)
+	}
+	if changes := pinata.Diff(from, from); len(changes) != 0 {
+		t.Error("a Pinata must not differ from itself", changes)
+	}
+
+	from = pinata.NewPinata(map[string]interface{}{"ID": int64(9007199254740993)})
+	to = pinata.NewPinata(map[string]interface{}{"ID": int64(9007199254740992)})
+	if changes := pinata.Diff(from, to); len(changes) != 1 {
+		t.Error("a changed large ID must be reported, got", changes)
+	}
+	if patch := pinata.CreatePatch(from, to); len(patch.Value().([]interface{})) != 1 {
+		t.Error("a changed large ID must be patched, got", patch.Value())
+	}
+}
@@ -0,0 +1,127 @@
+package pinata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Path is a sequence of map keys, as passed to Stick.Path.
+type Path []string
+
+// P creates a Path.
+func P(path ...string) Path {
+	return Path(path)
+}
+
+// GoString formats the Path the way it is created with P.
+func (p Path) GoString() string {
+	quoted := make([]string, len(p))
+	for i := range p {
+		quoted[i] = fmt.Sprintf("%q", p[i])
+	}
+	return "P(" + strings.Join(quoted, ", ") + ")"
+}
+
+func pathsToInterfaceSlice(paths []Path) []interface{} {
+	ifaces := make([]interface{}, len(paths))
+	for i := range paths {
+		ifaces[i] = paths[i]
+	}
+	return ifaces
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalFirstOf(p Pinata, methodName string, paths []Path) Pinata {
+	input := func() []interface{} { return pathsToInterfaceSlice(paths) }
+	if len(paths) == 0 {
+		s.err = &Error{
+			context: &ErrorContext{
+				methodName: methodName,
+				methodArgs: input,
+				source:     p.source,
+				next:       p.context,
+			},
+			reason: ErrorReasonInvalidInput,
+			advice: "specify at least one path",
+		}
+		return Pinata{}
+	}
+	if _, ok := p.Map(); !ok {
+		s.unsupported(p, methodName, input, "call this method on a map pinata")
+		return Pinata{}
+	}
+	// only the path found counts as read, not the attempts before it
+	visited := s.visited
+	s.visited = nil
+	defer func() { s.visited = visited }()
+	for _, path := range paths {
+		s.internalPath(p, methodName, path...)
+		if s.err == nil {
+			s.visited = visited
+			return s.internalPath(p, methodName, path...)
+		}
+		// as p is a map, an incompatible type means the path runs through a
+		// value that is not a map, so the path does not exist either
+		if err, ok := s.err.(*Error); !ok || (err.Reason() != ErrorReasonNotFound && err.Reason() != ErrorReasonIncompatibleType) {
+			return Pinata{}
+		}
+		s.err = nil
+	}
+	s.err = &Error{
+		context: &ErrorContext{
+			methodName: methodName,
+			methodArgs: input,
+			source:     p.source,
+			next:       p.context,
+		},
+		reason: ErrorReasonNotFound,
+		advice: "none of the paths exist",
+	}
+	return Pinata{}
+}
+
+func (s *stick) FirstOf(p Pinata, paths ...Path) Pinata {
+	if s.err != nil {
+		return Pinata{}
+	}
+	return s.internalFirstOf(p, "FirstOf", paths)
+}
+
+func (s *stick) FirstOfString(p Pinata, paths ...Path) string {
+	if s.err != nil {
+		return ""
+	}
+	const methodName = "FirstOfString"
+	pinata := s.internalFirstOf(p, methodName, paths)
+	if s.err != nil {
+		return ""
+	}
+	pinata.context = p.context
+	return s.internalString(pinata, methodName, func() []interface{} { return pathsToInterfaceSlice(paths) })
+}
+
+func (s *stick) FirstOfFloat64(p Pinata, paths ...Path) float64 {
+	if s.err != nil {
+		return 0
+	}
+	const methodName = "FirstOfFloat64"
+	pinata := s.internalFirstOf(p, methodName, paths)
+	if s.err != nil {
+		return 0
+	}
+	pinata.context = p.context
+	return s.internalFloat64(pinata, methodName, func() []interface{} { return pathsToInterfaceSlice(paths) })
+}
+
+func (s *stick) FirstOfBool(p Pinata, paths ...Path) bool {
+	if s.err != nil {
+		return false
+	}
+	const methodName = "FirstOfBool"
+	pinata := s.internalFirstOf(p, methodName, paths)
+	if s.err != nil {
+		return false
+	}
+	pinata.context = p.context
+	return s.internalBool(pinata, methodName, func() []interface{} { return pathsToInterfaceSlice(paths) })
+}
@@ -0,0 +1,55 @@
+package pinata_test
+
+import (
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestFirstOf(t *testing.T) {
+	stick, thePinata := start(t)
+
+	if v := stick.FirstOfString(thePinata, pinata.P("User", "Name"), pinata.P("Name")); v != "Kevin" {
+		t.Error("Name must be found as a fallback, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	stick.FirstOfString(thePinata, pinata.P("Address", "Town"), pinata.P("Address", "City"))
+	if err := stick.ClearError(); err == nil {
+		t.Error("an existing path must still be type checked")
+	}
+
+	stick.FirstOf(thePinata, pinata.P("User", "Name"), pinata.P("Username"))
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("missing paths must result in an error")
+	}
+	const expected = `pinata: not found (none of the paths exist) at FirstOf(P("User", "Name"), P("Username"))`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	if v := stick.FirstOfString(thePinata, pinata.P("Name", "First"), pinata.P("Name")); v != "Kevin" {
+		t.Error("a path through a non-map must fall back, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := pinata.NewPinata(map[string]interface{}{"user": "legacy", "username": "x"})
+	if v := stick.FirstOfString(p, pinata.P("user", "name"), pinata.P("username")); v != "x" {
+		t.Error("username must be found after the legacy scalar user, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	stick.FirstOf(pinata.NewPinata("scalar"), pinata.P("Name"))
+	if err := stick.ClearError(); err == nil {
+		t.Error("a Pinata that is not a map must result in an error")
+	} else if err.(*pinata.Error).Reason() != pinata.ErrorReasonIncompatibleType {
+		t.Error("error reason must be incompatible type")
+	}
+}
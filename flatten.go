@@ -0,0 +1,206 @@
+package pinata
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FlattenOptions configures FlattenWith and UnflattenWith.
+type FlattenOptions struct {
+	// Separator joins the keys of a path, a dot if empty. Keys holding the
+	// separator or a backslash escape it with a backslash.
+	Separator string
+}
+
+func (o FlattenOptions) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+// Flatten is FlattenWith using the default options.
+func Flatten(p Pinata) map[string]interface{} {
+	return FlattenWith(FlattenOptions{}, p)
+}
+
+// FlattenWith returns a map from the path of every value within the Pinata to
+// the value, where map keys and slice indices are joined with the separator,
+// for example "Hobbies.0.Indoors.1". Empty maps and slices are kept as values
+// so Unflatten can restore them. A Pinata that holds neither a map nor a
+// slice results in its value stored under the empty key. A map holding a
+// single value under the empty key, such as {"": 1}, flattens to the same
+// result, so it does not survive a round trip and Unflatten returns the value
+// itself.
+func FlattenWith(opts FlattenOptions, p Pinata) map[string]interface{} {
+	result := make(map[string]interface{})
+	flatten(p.Value(), "", true, opts.separator(), result)
+	return result
+}
+
+func flatten(value interface{}, prefix string, root bool, separator string, result map[string]interface{}) {
+	join := func(key string) string {
+		key = escapeFlatKey(key, separator)
+		if root {
+			return key
+		}
+		return prefix + separator + key
+	}
+	switch t := value.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 && !root {
+			result[prefix] = map[string]interface{}{}
+		}
+		for k, v := range t {
+			flatten(v, join(k), false, separator, result)
+		}
+	case []interface{}:
+		if len(t) == 0 && !root {
+			result[prefix] = []interface{}{}
+		}
+		for i, v := range t {
+			flatten(v, join(strconv.Itoa(i)), false, separator, result)
+		}
+	default:
+		result[prefix] = value
+	}
+}
+
+func escapeFlatKey(key, separator string) string {
+	if !strings.Contains(key, `\`) && !strings.Contains(key, separator) {
+		return key
+	}
+	key = strings.ReplaceAll(key, `\`, `\\`)
+	return strings.ReplaceAll(key, separator, `\`+separator)
+}
+
+// splitFlatKey splits a flattened key into its path, undoing the escaping
+// (the bool indicates success).
+func splitFlatKey(key, separator string) ([]string, bool) {
+	var path []string
+	var b strings.Builder
+	for i := 0; i < len(key); {
+		switch {
+		case key[i] == '\\':
+			if i+1 == len(key) {
+				return nil, false
+			}
+			if strings.HasPrefix(key[i+1:], separator) {
+				_, _ = b.WriteString(separator)
+				i += 1 + len(separator)
+			} else {
+				_ = b.WriteByte(key[i+1])
+				i += 2
+			}
+		case strings.HasPrefix(key[i:], separator):
+			path = append(path, b.String())
+			b.Reset()
+			i += len(separator)
+		default:
+			_ = b.WriteByte(key[i])
+			i++
+		}
+	}
+	return append(path, b.String()), true
+}
+
+// Unflatten is UnflattenWith using the default options.
+func Unflatten(m map[string]interface{}) (Pinata, error) {
+	return UnflattenWith(FlattenOptions{}, m)
+}
+
+// UnflattenWith rebuilds the tree of maps and slices described by a map as
+// returned by FlattenWith. Maps whose keys are exactly the indices 0 to n-1
+// become slices. A map with the empty key as its only key holding neither a
+// map nor a slice results in that value, see FlattenWith.
+//
+// The error is a *Error if a key is malformed or two keys conflict, for
+// example "a" and "a.b".
+func UnflattenWith(opts FlattenOptions, m map[string]interface{}) (Pinata, error) {
+	separator := opts.separator()
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fail := func(key, advice string) (Pinata, error) {
+		return Pinata{}, &Error{
+			context: &ErrorContext{
+				methodName: "Unflatten",
+				methodArgs: func() []interface{} { return []interface{}{key} },
+			},
+			reason: ErrorReasonInvalidInput,
+			advice: advice,
+		}
+	}
+
+	if v, ok := m[""]; ok && len(m) == 1 {
+		if t := schemaType(v); t != "object" && t != "array" {
+			return NewPinata(v), nil
+		}
+	}
+
+	root := &flatNode{}
+	for _, key := range keys {
+		path, ok := splitFlatKey(key, separator)
+		if !ok {
+			return fail(key, fmt.Sprintf("%q ends with an incomplete escape", key))
+		}
+		node := root
+		for i, token := range path {
+			if node.leaf {
+				return fail(key, fmt.Sprintf("%q conflicts with %q", key, node.key))
+			}
+			if node.children == nil {
+				node.children = make(map[string]*flatNode)
+			}
+			child, ok := node.children[token]
+			if !ok {
+				child = &flatNode{}
+				node.children[token] = child
+			}
+			node = child
+			if i == len(path)-1 {
+				if node.children != nil {
+					return fail(key, fmt.Sprintf("%q conflicts with another key", key))
+				}
+				node.leaf = true
+				node.key = key
+				node.value = m[key]
+			}
+		}
+	}
+	return NewPinata(root.build()), nil
+}
+
+type flatNode struct {
+	leaf     bool
+	key      string
+	value    interface{}
+	children map[string]*flatNode
+}
+
+func (n *flatNode) build() interface{} {
+	if n.leaf {
+		return deepCopy(n.value)
+	}
+	dense := true
+	for i := 0; i < len(n.children) && dense; i++ {
+		_, dense = n.children[strconv.Itoa(i)]
+	}
+	if dense && len(n.children) > 0 {
+		s := make([]interface{}, len(n.children))
+		for i := range s {
+			s[i] = n.children[strconv.Itoa(i)].build()
+		}
+		return s
+	}
+	m := make(map[string]interface{}, len(n.children))
+	for k, child := range n.children {
+		m[k] = child.build()
+	}
+	return m
+}
@@ -0,0 +1,111 @@
+package pinata_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestFlatten(t *testing.T) {
+	p := decode(t, `{
+		"Name": "Gopher",
+		"Hobbies": [{"Indoors": ["chess", "go"]}],
+		"a.b": {"c\\d": 1},
+		"Empty": {},
+		"None": [],
+		"Nil": null
+	}`)
+	flat := pinata.Flatten(p)
+	expected := map[string]interface{}{
+		"Name":                "Gopher",
+		"Hobbies.0.Indoors.0": "chess",
+		"Hobbies.0.Indoors.1": "go",
+		`a\.b.c\\d`:           1.0,
+		"Empty":               map[string]interface{}{},
+		"None":                []interface{}{},
+		"Nil":                 nil,
+	}
+	if !reflect.DeepEqual(flat, expected) {
+		t.Errorf("expected %v, got %v", expected, flat)
+	}
+
+	unflattened, err := pinata.Unflatten(flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pinata.Equal(p, unflattened) {
+		t.Errorf("expected %v, got %v", p.Value(), unflattened.Value())
+	}
+
+	opts := pinata.FlattenOptions{Separator: "/"}
+	flat = pinata.FlattenWith(opts, p)
+	if flat["Hobbies/0/Indoors/1"] != "go" || flat["a.b/c\\\\d"] != 1.0 {
+		t.Errorf("unexpected keys in %v", flat)
+	}
+	unflattened, err = pinata.UnflattenWith(opts, flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pinata.Equal(p, unflattened) {
+		t.Errorf("expected %v, got %v", p.Value(), unflattened.Value())
+	}
+
+	flat = pinata.Flatten(pinata.NewPinata("scalar"))
+	unflattened, err = pinata.Unflatten(flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unflattened.Value() != "scalar" {
+		t.Errorf("expected scalar, got %v", unflattened.Value())
+	}
+}
+
+func TestFlattenEmptyKey(t *testing.T) {
+	// a map with only the empty key flattens like a scalar, so the map is lost
+	flat := pinata.Flatten(pinata.NewPinata(map[string]interface{}{"": 1.0}))
+	if !reflect.DeepEqual(flat, pinata.Flatten(pinata.NewPinata(1.0))) {
+		t.Errorf("expected the flattened scalar, got %v", flat)
+	}
+	p, err := pinata.Unflatten(flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Value() != 1.0 {
+		t.Errorf("expected the scalar 1, got %v", p.Value())
+	}
+
+	// next to other keys the empty key survives
+	original := decode(t, `{"": 1, "a": 2}`)
+	p, err = pinata.Unflatten(pinata.Flatten(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pinata.Equal(p, original) {
+		t.Errorf("expected %v, got %v", original.Value(), p.Value())
+	}
+}
+
+func TestUnflattenErrors(t *testing.T) {
+	for _, m := range []map[string]interface{}{
+		{"a": 1.0, "a.b": 2.0},
+		{"a": map[string]interface{}{}, "a.b": 2.0},
+		{`a\`: 1.0},
+	} {
+		_, err := pinata.Unflatten(m)
+		if err == nil {
+			t.Errorf("%v must result in an error", m)
+		} else if _, ok := err.(*pinata.Error); !ok {
+			t.Errorf("%v must result in a *pinata.Error, got %T", m, err)
+		}
+	}
+
+	p, err := pinata.Unflatten(map[string]interface{}{"a.0": 1.0, "a.2": 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decode(t, `{"a": {"0": 1, "2": 2}}`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("sparse indices must remain a map, expected %v, got %v", expected.Value(), p.Value())
+	}
+}
@@ -0,0 +1,67 @@
+package pinata
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+)
+
+// Hash returns the digest of the RFC 8785 canonical JSON form of the Pinata
+// value, see EncodeOptions.Canonical. Equal values have the same digest
+// regardless of map order or how their numbers were written, so it can be
+// used to detect whether part of a document changed. Unlike RFC 8785, integer
+// types and integral json.Number values are hashed exactly, so a change to a
+// large integer ID beyond 2^53 changes the digest. The hash function must be
+// linked into the binary, for example by importing crypto/sha256.
+func Hash(p Pinata, alg crypto.Hash) ([]byte, error) {
+	if !alg.Available() {
+		return nil, fmt.Errorf("pinata: hash function %v is not available", alg)
+	}
+	var b bytes.Buffer
+	if err := canonicalize(&b, p.value, true); err != nil {
+		return nil, err
+	}
+	h := alg.New()
+	_, _ = h.Write(b.Bytes())
+	return h.Sum(nil), nil
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalHash(p Pinata, methodName string, alg crypto.Hash, input func() []interface{}) []byte {
+	fail := func(reason ErrorReason, advice string) []byte {
+		s.err = &Error{
+			context: &ErrorContext{
+				methodName: methodName,
+				methodArgs: input,
+				source:     p.source,
+				next:       p.context,
+			},
+			reason: reason,
+			advice: advice,
+		}
+		return nil
+	}
+	if !alg.Available() {
+		return fail(ErrorReasonInvalidInput, fmt.Sprintf("hash function %v is not available", alg))
+	}
+	digest, err := Hash(p, alg)
+	if err != nil {
+		return fail(ErrorReasonIncompatibleType, err.Error())
+	}
+	return digest
+}
+
+func (s *stick) PathHash(p Pinata, alg crypto.Hash, path ...string) []byte {
+	if s.err != nil {
+		return nil
+	}
+	const methodName = "PathHash"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return nil
+	}
+	pinata.context = p.context
+	return s.internalHash(pinata, methodName, alg, func() []interface{} {
+		return append([]interface{}{alg.String()}, toInterfaceSlice(path)...)
+	})
+}
@@ -0,0 +1,99 @@
+package pinata_test
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestHash(t *testing.T) {
+	a, err := pinata.Hash(decode(t, `{"b": [1.0, 2e0], "a": "x"}`), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := pinata.Hash(decode(t, `{"a": "x", "b": [1, 2]}`), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("equal values must have the same digest")
+	}
+	// the SHA-256 digest of {"a":"x","b":[1,2]}
+	const expected = "721ef82f2d6c0997bffb7a8ab3f40f8fb45b0b52ce2af3afa6b0f05efbdc317f"
+	if hex.EncodeToString(a) != expected {
+		t.Errorf("expected %s, got %s", expected, hex.EncodeToString(a))
+	}
+	c, err := pinata.Hash(decode(t, `{"a": "y", "b": [1, 2]}`), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, c) {
+		t.Error("different values must have different digests")
+	}
+
+	for _, pair := range [][2]interface{}{
+		{int64(9007199254740993), int64(9007199254740992)},
+		{uint64(18446744073709551615), uint64(18446744073709551614)},
+		{json.Number("12345678901234567891"), json.Number("12345678901234567890")},
+	} {
+		x, err := pinata.Hash(pinata.NewPinata(map[string]interface{}{"id": pair[0]}), crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := pinata.Hash(pinata.NewPinata(map[string]interface{}{"id": pair[1]}), crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(x, y) {
+			t.Errorf("%v and %v must have different digests", pair[0], pair[1])
+		}
+	}
+	small, _ := pinata.Hash(pinata.NewPinata(map[string]interface{}{"id": int64(42)}), crypto.SHA256)
+	float, _ := pinata.Hash(pinata.NewPinata(map[string]interface{}{"id": 42.0}), crypto.SHA256)
+	if !bytes.Equal(small, float) {
+		t.Error("an int64 and a float64 holding the same small value must have the same digest")
+	}
+	for _, pair := range [][2]interface{}{
+		{int64(1 << 60), float64(1 << 60)},
+		{json.Number("100000000000000000000"), 1e20},
+	} {
+		x, _ := pinata.Hash(pinata.NewPinata(pair[0]), crypto.SHA256)
+		y, _ := pinata.Hash(pinata.NewPinata(pair[1]), crypto.SHA256)
+		if !pinata.Equal(pinata.NewPinata(pair[0]), pinata.NewPinata(pair[1])) || !bytes.Equal(x, y) {
+			t.Errorf("%v and %v must be equal and have the same digest", pair[0], pair[1])
+		}
+	}
+
+	if _, err := pinata.Hash(decode(t, `{}`), crypto.Hash(0)); err == nil {
+		t.Error("an unavailable hash function must result in an error")
+	}
+}
+
+func TestPathHash(t *testing.T) {
+	p := decode(t, `{"upstream": {"items": [1, 2]}, "fetched": "2024-01-01"}`)
+	stick := pinata.NewStick()
+	digest := stick.PathHash(p, crypto.SHA256, "upstream")
+	if err := stick.Error(); err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := pinata.Hash(decode(t, `{"items": [1, 2]}`), crypto.SHA256)
+	if !bytes.Equal(digest, expected) {
+		t.Errorf("expected %s, got %s", hex.EncodeToString(expected), hex.EncodeToString(digest))
+	}
+
+	stick.PathHash(p, crypto.SHA256, "missing")
+	const expectedErr = `pinata: not found ("missing" does not exist) at PathHash("missing")`
+	if err := stick.ClearError(); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected %q, got %v", expectedErr, err)
+	}
+
+	stick.PathHash(p, crypto.Hash(0), "upstream")
+	if err := stick.ClearError(); err == nil {
+		t.Error("an unavailable hash function must result in an error")
+	}
+}
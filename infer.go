@@ -0,0 +1,103 @@
+package pinata
+
+import (
+	"sort"
+)
+
+// shape accumulates what was observed at one location across samples.
+type shape struct {
+	types      map[string]bool
+	objects    int // the number of objects observed
+	present    int // the number of parent objects holding this property
+	properties map[string]*shape
+	items      *shape
+}
+
+func newShape() *shape {
+	return &shape{types: make(map[string]bool)}
+}
+
+func (s *shape) observe(value interface{}) {
+	t := schemaType(value)
+	if t == "number" && hasType(value, "integer") {
+		t = "integer"
+	}
+	s.types[t] = true
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.objects++
+		if s.properties == nil {
+			s.properties = make(map[string]*shape)
+		}
+		for k := range v {
+			prop, ok := s.properties[k]
+			if !ok {
+				prop = newShape()
+				s.properties[k] = prop
+			}
+			prop.present++
+			prop.observe(v[k])
+		}
+	case []interface{}:
+		if s.items == nil {
+			s.items = newShape()
+		}
+		for i := range v {
+			s.items.observe(v[i])
+		}
+	}
+}
+
+func (s *shape) schema() map[string]interface{} {
+	schema := make(map[string]interface{})
+	if s.types["integer"] && s.types["number"] {
+		delete(s.types, "integer")
+	}
+	types := make([]string, 0, len(s.types))
+	for t := range s.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	switch len(types) {
+	case 0:
+		return schema
+	case 1:
+		schema["type"] = types[0]
+	default:
+		schema["type"] = toInterfaceSlice(types)
+	}
+	if s.properties != nil {
+		properties := make(map[string]interface{}, len(s.properties))
+		var required []string
+		for k, prop := range s.properties {
+			properties[k] = prop.schema()
+			if prop.present == s.objects {
+				required = append(required, k)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = toInterfaceSlice(required)
+		}
+	}
+	if s.items != nil && len(s.items.types) > 0 {
+		schema["items"] = s.items.schema()
+	}
+	return schema
+}
+
+// InferSchema returns a JSON Schema describing all of the samples. Keys that
+// are missing from some objects are left out of "required", values that are
+// sometimes null or of different types get a list of types, and array items
+// are described by a single schema covering every element seen. The result
+// can be passed to CompileSchema.
+func InferSchema(samples ...Pinata) Pinata {
+	s := newShape()
+	for _, sample := range samples {
+		s.observe(sample.Value())
+	}
+	schema := s.schema()
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return NewPinata(schema)
+}
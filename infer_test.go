@@ -0,0 +1,43 @@
+package pinata_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestInferSchema(t *testing.T) {
+	samples := []pinata.Pinata{
+		decode(t, `{"id": 1, "name": "Kevin", "tags": ["a"], "score": 1, "address": {"city": null}}`),
+		decode(t, `{"id": 2, "name": "Bob", "tags": [], "score": 2.5, "address": {"city": "Gophertown"}}`),
+		decode(t, `{"id": 3, "tags": ["b", 3], "address": {"city": "Gopherville", "zip": "G0"}}`),
+	}
+	inferred := pinata.InferSchema(samples...)
+
+	b, err := json.Marshal(inferred.Value())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"$schema":"https://json-schema.org/draft/2020-12/schema",` +
+		`"properties":{` +
+		`"address":{"properties":{"city":{"type":["null","string"]},"zip":{"type":"string"}},"required":["city"],"type":"object"},` +
+		`"id":{"type":"integer"},` +
+		`"name":{"type":"string"},` +
+		`"score":{"type":"number"},` +
+		`"tags":{"items":{"type":["integer","string"]},"type":"array"}},` +
+		`"required":["address","id","tags"],"type":"object"}`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	schema, err := pinata.CompileSchema(inferred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sample := range samples {
+		if errs := schema.Validate(sample); len(errs) != 0 {
+			t.Error("samples must be valid against the inferred schema", errs)
+		}
+	}
+}
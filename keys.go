@@ -0,0 +1,61 @@
+package pinata
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyNormalizer maps a map key to a normal form. Two keys with the same normal
+// form are considered a match when looking up a path.
+type KeyNormalizer func(string) string
+
+// IgnoreCase matches keys case-insensitively, like encoding/json does for
+// struct fields.
+func IgnoreCase(key string) string {
+	return strings.ToLower(key)
+}
+
+// IgnoreCaseAndSeparators matches keys case-insensitively and ignores
+// underscores and dashes, so "user_name", "user-name" and "userName" all
+// match.
+func IgnoreCaseAndSeparators(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(key))
+}
+
+// WithKeyNormalizer makes path lookups fall back to comparing normalized keys
+// when a map holds no exact match. A lookup that matches more than one key
+// results in an error.
+func WithKeyNormalizer(normalize KeyNormalizer) Option {
+	return func(s *stick) {
+		s.normalize = normalize
+	}
+}
+
+// lookup finds the key in contents that matches key. If the key is ambiguous
+// the matching keys are returned instead.
+func (s *stick) lookup(contents map[string]interface{}, key string) (string, bool, []string) {
+	if _, ok := contents[key]; ok || s.normalize == nil {
+		return key, ok, nil
+	}
+	want := s.normalize(key)
+	var candidates []string
+	for k := range contents {
+		if s.normalize(k) == want {
+			candidates = append(candidates, k)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return candidates[0], true, nil
+	default:
+		sort.Strings(candidates)
+		return "", false, candidates
+	}
+}
@@ -0,0 +1,50 @@
+package pinata_test
+
+import (
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestKeyNormalizer(t *testing.T) {
+	p := pinata.NewPinata(map[string]interface{}{
+		"UserName": "kevin",
+		"Address": map[string]interface{}{
+			"postal_code": "G0 PHR",
+		},
+		"phoneNumber":  "+44 20 7123 4567",
+		"phone_number": "+44 20 4567 7123",
+	})
+
+	{
+		stick := pinata.NewStick()
+		stick.PathString(p, "username")
+		if err := stick.ClearError(); err == nil {
+			t.Error("keys must match exactly by default")
+		}
+	}
+
+	stick := pinata.NewStick(pinata.WithKeyNormalizer(pinata.IgnoreCaseAndSeparators))
+	if v := stick.PathString(p, "user_name"); v != "kevin" {
+		t.Error("user_name must match UserName, got", v)
+	}
+	if v := stick.PathString(p, "address", "postalCode"); v != "G0 PHR" {
+		t.Error("address/postalCode must match Address/postal_code, got", v)
+	}
+	if v := stick.PathString(p, "phone_number"); v != "+44 20 4567 7123" {
+		t.Error("an exact match must win over a normalized one, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	stick.PathString(p, "PhoneNumber")
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("ambiguous key must result in an error")
+	}
+	t.Log(err)
+	if err.(*pinata.Error).Reason() != pinata.ErrorReasonInvalidInput {
+		t.Error("error reason must be invalid input")
+	}
+}
@@ -0,0 +1,228 @@
+package pinata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// MarshalJSON encodes the Pinata value as JSON with sorted map keys, so a
+// Pinata can be passed to json.Marshal directly.
+func (p Pinata) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.value)
+}
+
+// EncodeOptions configures Encode.
+type EncodeOptions struct {
+	// Indent indents nested values with the string, such as "  ", and puts
+	// every element on its own line. Values are compact if it is empty.
+	Indent string
+	// EscapeHTML escapes <, > and & within strings so the output can be
+	// embedded in HTML.
+	EscapeHTML bool
+	// Canonical writes the RFC 8785 JSON Canonicalization Scheme form, which
+	// is identical for equal values and suitable for signing and hashing. Map
+	// keys are sorted by their UTF-16 code units, numbers are formatted like
+	// ECMAScript does and there is no white space. Indent and EscapeHTML are
+	// ignored.
+	Canonical bool
+}
+
+// Encode writes the Pinata value as JSON to w with map keys in sorted order.
+// Unless the output is canonical it is followed by a newline, like
+// json.Encoder does.
+func Encode(w io.Writer, p Pinata, opts EncodeOptions) error {
+	if !opts.Canonical {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", opts.Indent)
+		encoder.SetEscapeHTML(opts.EscapeHTML)
+		return encoder.Encode(p.value)
+	}
+	var b bytes.Buffer
+	if err := canonicalize(&b, p.value, false); err != nil {
+		return err
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// canonicalize writes the RFC 8785 form of the value. With exactIntegers
+// integer types and integral json.Number values are written as they are
+// rather than as the nearest float64, and integral floats from 2^53 on are
+// written in full, so integers and floats of equal value match. This only
+// differs from RFC 8785 above 2^53.
+func canonicalize(b *bytes.Buffer, value interface{}, exactIntegers bool) error {
+	switch t := value.(type) {
+	case nil:
+		_, _ = b.WriteString("null")
+	case bool:
+		_, _ = b.WriteString(strconv.FormatBool(t))
+	case string:
+		return canonicalString(b, t)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+		_ = b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				_ = b.WriteByte(',')
+			}
+			if err := canonicalString(b, k); err != nil {
+				return err
+			}
+			_ = b.WriteByte(':')
+			if err := canonicalize(b, t[k], exactIntegers); err != nil {
+				return err
+			}
+		}
+		_ = b.WriteByte('}')
+	case []interface{}:
+		_ = b.WriteByte('[')
+		for i := range t {
+			if i > 0 {
+				_ = b.WriteByte(',')
+			}
+			if err := canonicalize(b, t[i], exactIntegers); err != nil {
+				return err
+			}
+		}
+		_ = b.WriteByte(']')
+	default:
+		if exactIntegers {
+			if s, ok := exactInteger(value); ok {
+				_, _ = b.WriteString(s)
+				return nil
+			}
+			// large integral floats are written like the integers they equal
+			if f, ok := number(value); ok && f == math.Trunc(f) && math.Abs(f) >= 1<<53 && !math.IsInf(f, 0) {
+				_, _ = b.WriteString(strconv.FormatFloat(f, 'f', 0, 64))
+				return nil
+			}
+		}
+		if f, ok := number(value); ok {
+			return canonicalNumber(b, f)
+		}
+		// other values, such as time.Time or structs, are canonicalized
+		// through the JSON they marshal to
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		return canonicalize(b, decoded, exactIntegers)
+	}
+	return nil
+}
+
+// exactInteger formats an integer value in decimal (the bool indicates
+// success).
+func exactInteger(value interface{}) (string, bool) {
+	if i, ok := integer(value); ok {
+		return strconv.FormatInt(i, 10), true
+	}
+	if u, ok := unsignedInteger(value); ok {
+		return strconv.FormatUint(u, 10), true
+	}
+	n, ok := value.(json.Number)
+	if !ok {
+		return "", false
+	}
+	digits := strings.TrimPrefix(string(n), "-")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" || (len(digits) > 1 && digits[0] == '0') {
+		return "", false
+	}
+	if digits == "0" {
+		// this includes negative zero
+		return "0", true
+	}
+	return string(n), true
+}
+
+// canonicalNumber formats the number like ECMAScript's Number.prototype.toString.
+func canonicalNumber(b *bytes.Buffer, f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("pinata: %v cannot be encoded as JSON", f)
+	}
+	if f == 0 {
+		// this includes negative zero
+		_ = b.WriteByte('0')
+		return nil
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	s := strconv.FormatFloat(f, format, -1, 64)
+	if format == 'e' {
+		// shorten exponents such as e-07 to e-7
+		if n := len(s); n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+	}
+	_, _ = b.WriteString(s)
+	return nil
+}
+
+// canonicalString writes the string quoted, escaping only what JSON requires.
+// Invalid UTF-8 is an error as RFC 8785 requires.
+func canonicalString(b *bytes.Buffer, s string) error {
+	const hex = "0123456789abcdef"
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("pinata: %q is not valid UTF-8", s)
+	}
+	_ = b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			_, _ = b.WriteString(`\"`)
+		case '\\':
+			_, _ = b.WriteString(`\\`)
+		case '\b':
+			_, _ = b.WriteString(`\b`)
+		case '\f':
+			_, _ = b.WriteString(`\f`)
+		case '\n':
+			_, _ = b.WriteString(`\n`)
+		case '\r':
+			_, _ = b.WriteString(`\r`)
+		case '\t':
+			_, _ = b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				_, _ = b.WriteString(`\u00`)
+				_ = b.WriteByte(hex[r>>4])
+				_ = b.WriteByte(hex[r&0xf])
+				continue
+			}
+			_, _ = b.WriteRune(r)
+		}
+	}
+	_ = b.WriteByte('"')
+	return nil
+}
+
+// lessUTF16 compares strings by their UTF-16 code units, as RFC 8785 sorts
+// map keys.
+func lessUTF16(a, b string) bool {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
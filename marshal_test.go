@@ -0,0 +1,98 @@
+package pinata_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	p := decode(t, `{"b": [1, true, null], "a": "x"}`)
+	data, err := json.Marshal(map[string]interface{}{"doc": p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"doc":{"a":"x","b":[1,true,null]}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	p := decode(t, `{"b": "<b>", "a": [1]}`)
+
+	var b strings.Builder
+	if err := pinata.Encode(&b, p, pinata.EncodeOptions{Indent: "  "}); err != nil {
+		t.Fatal(err)
+	}
+	const expected = "{\n  \"a\": [\n    1\n  ],\n  \"b\": \"<b>\"\n}\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	if err := pinata.Encode(&b, p, pinata.EncodeOptions{EscapeHTML: true}); err != nil {
+		t.Fatal(err)
+	}
+	const expectedEscaped = "{\"a\":[1],\"b\":\"\\u003cb\\u003e\"}\n"
+	if b.String() != expectedEscaped {
+		t.Errorf("expected %q, got %q", expectedEscaped, b.String())
+	}
+}
+
+func TestEncodeCanonical(t *testing.T) {
+	// the examples from RFC 8785 sections 3.2.2 and 3.2.3
+	for _, test := range []struct {
+		input    string
+		expected string
+	}{
+		{
+			input: `{
+				"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+				"string": "€$\u000F\u000aA'B\"\\\\\"\/",
+				"literals": [null, true, false]
+			}`,
+			expected: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			input: `{
+				"€": "Euro Sign",
+				"\r": "Carriage Return",
+				"דּ": "Hebrew Letter Dalet With Dagesh",
+				"1": "One",
+				"😀": "Emoji: Grinning Face",
+				"\u0080": "Control",
+				"ö": "Latin Small Letter O With Diaeresis"
+			}`,
+			expected: `{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control","ö":"Latin Small Letter O With Diaeresis","€":"Euro Sign","😀":"Emoji: Grinning Face","דּ":"Hebrew Letter Dalet With Dagesh"}`,
+		},
+		{
+			input:    `[-0, 1e21, 1e-7, 0.000001, 100, "<&>"]`,
+			expected: `[0,1e+21,1e-7,0.000001,100,"<&>"]`,
+		},
+	} {
+		var b strings.Builder
+		if err := pinata.Encode(&b, decode(t, test.input), pinata.EncodeOptions{Canonical: true, Indent: "  "}); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != test.expected {
+			t.Errorf("expected %s, got %s", test.expected, b.String())
+		}
+	}
+
+	var b strings.Builder
+	if err := pinata.Encode(&b, pinata.NewPinata(math.NaN()), pinata.EncodeOptions{Canonical: true}); err == nil {
+		t.Error("NaN must not be encoded")
+	}
+	for _, invalid := range []interface{}{
+		"\xff",
+		map[string]interface{}{"\xfe": "x"},
+	} {
+		if err := pinata.Encode(&b, pinata.NewPinata(invalid), pinata.EncodeOptions{Canonical: true}); err == nil {
+			t.Errorf("invalid UTF-8 in %q must not be encoded", invalid)
+		}
+	}
+}
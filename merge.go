@@ -0,0 +1,241 @@
+package pinata
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// MergePatch applies an RFC 7396 JSON Merge Patch to the target and returns
+// the result: maps in the patch are merged into the target recursively, a
+// null value removes the key and any other value replaces what the target
+// holds. The target itself is not modified.
+func MergePatch(target, patch Pinata) Pinata {
+	p := newPinataWithContext(mergePatch(deepCopy(target.Value()), patch.Value()), target.context)
+	p.source = target.source
+	return p
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return deepCopy(patch)
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+// ArrayStrategy determines how Merge combines two slices.
+type ArrayStrategy int
+
+const (
+	// ArrayReplace replaces the slice of a layer with the slice of a later
+	// layer.
+	ArrayReplace ArrayStrategy = iota
+	// ArrayAppend appends the elements of the slice of a later layer.
+	ArrayAppend
+	// ArrayMergeByKey merges maps in the slice of a later layer into the maps
+	// that hold the same value for MergeOptions.ArrayKey, and appends the
+	// other elements.
+	ArrayMergeByKey
+)
+
+// MergeOptions configures MergeWith.
+type MergeOptions struct {
+	// Arrays is the strategy used to combine slices.
+	Arrays ArrayStrategy
+	// ArrayKey identifies the elements of slices for ArrayMergeByKey.
+	ArrayKey string
+	// LayerNames names the layers in the order they are passed, for use by
+	// Pinata.Origin and in errors. A layer without a name is known by its
+	// source (see NewPinataFromSource) or else as "layer 1", "layer 2" and so
+	// on.
+	LayerNames []string
+}
+
+// Merge is MergeWith using the default options.
+func Merge(layers ...Pinata) Pinata {
+	return MergeWith(MergeOptions{}, layers...)
+}
+
+// MergeWith deep merges the layers into a new Pinata, a later layer taking
+// precedence over an earlier one. Maps are merged key by key, slices
+// according to the array strategy and anything else is replaced. Unlike
+// MergePatch a null value is kept. The layers themselves are not modified.
+//
+// The layer that supplied each value is recorded: Pinata.Origin reports it
+// and errors caused by a value mention it.
+func MergeWith(opts MergeOptions, layers ...Pinata) Pinata {
+	m := merger{
+		opts:    opts,
+		sources: make(map[slot]string),
+	}
+	var result interface{}
+	for i, layer := range layers {
+		name := layer.Source()
+		if i < len(opts.LayerNames) && opts.LayerNames[i] != "" {
+			name = opts.LayerNames[i]
+		} else if name == "" {
+			name = fmt.Sprintf("layer %d", i+1)
+		}
+		if i == 0 {
+			result = m.copy(layer, layer.Value(), name)
+			continue
+		}
+		result = m.merge(layer, result, layer.Value(), name)
+	}
+	p := newPinataWithContext(result, nil)
+	p.sources = m.sources
+	return p
+}
+
+type merger struct {
+	opts    MergeOptions
+	sources map[slot]string
+}
+
+// copy deep copies v from the layer, recording source as the source of
+// everything within unless the layer knows better.
+func (m *merger) copy(layer Pinata, v interface{}, source string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(t))
+		for k := range t {
+			childSource := layer.sourceOf(t, k, source)
+			c[k] = m.copy(layer, t[k], childSource)
+			m.sources[slotOf(c, k)] = childSource
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(t))
+		for i := range t {
+			key := strconv.Itoa(i)
+			childSource := layer.sourceOf(t, key, source)
+			c[i] = m.copy(layer, t[i], childSource)
+			m.sources[slotOf(c, key)] = childSource
+		}
+		return c
+	}
+	return v
+}
+
+// grow returns a copy of s with room for n more elements, keeping the
+// sources recorded for the elements of s.
+func (m *merger) grow(s []interface{}, n int) []interface{} {
+	c := make([]interface{}, len(s), len(s)+n)
+	copy(c, s)
+	for i := range s {
+		key := strconv.Itoa(i)
+		m.sources[slotOf(c, key)] = m.sources[slotOf(s, key)]
+		delete(m.sources, slotOf(s, key))
+	}
+	return c
+}
+
+// merge merges src from the layer into dst, which was created by the merger,
+// and returns the result.
+func (m *merger) merge(layer Pinata, dst, src interface{}, source string) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(s))
+		for k := range s {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childSource := layer.sourceOf(s, k, source)
+			if existing, ok := d[k]; ok {
+				d[k] = m.merge(layer, existing, s[k], childSource)
+			} else {
+				d[k] = m.copy(layer, s[k], childSource)
+			}
+			m.sources[slotOf(d, k)] = childSource
+		}
+		return d
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok || m.opts.Arrays == ArrayReplace {
+			break
+		}
+		d = m.grow(d, len(s))
+		for i := range s {
+			childSource := layer.sourceOf(s, strconv.Itoa(i), source)
+			if m.opts.Arrays == ArrayMergeByKey {
+				if j, ok := m.find(d, s[i]); ok {
+					d[j] = m.merge(layer, d[j], s[i], childSource)
+					m.sources[slotOf(d, strconv.Itoa(j))] = childSource
+					continue
+				}
+			}
+			d = append(d, m.copy(layer, s[i], childSource))
+			m.sources[slotOf(d, strconv.Itoa(len(d)-1))] = childSource
+		}
+		return d
+	}
+	return m.copy(layer, src, source)
+}
+
+// find returns the index of the map within d that holds the same value for
+// the array key as elem (the bool indicates success).
+func (m *merger) find(d []interface{}, elem interface{}) (int, bool) {
+	e, ok := elem.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	key, ok := e[m.opts.ArrayKey]
+	if !ok {
+		return 0, false
+	}
+	for i := range d {
+		if candidate, ok := d[i].(map[string]interface{}); ok {
+			if v, ok := candidate[m.opts.ArrayKey]; ok && equalValues(v, key) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Origin returns the name of the layer that supplied the value at the given
+// path within a Pinata created by Merge, or more generally the source of that
+// value. Slice elements are addressed by their index. The bool indicates
+// whether the path exists and its origin is known.
+func (p Pinata) Origin(path ...string) (string, bool) {
+	current := p.Value()
+	source := p.source
+	for _, key := range path {
+		switch t := current.(type) {
+		case map[string]interface{}:
+			v, ok := t[key]
+			if !ok {
+				return "", false
+			}
+			source = p.sourceOf(t, key, source)
+			current = v
+		case []interface{}:
+			i, ok := arrayIndex(key, len(t))
+			if !ok {
+				return "", false
+			}
+			source = p.sourceOf(t, key, source)
+			current = t[i]
+		default:
+			return "", false
+		}
+	}
+	return source, source != ""
+}
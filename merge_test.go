@@ -0,0 +1,83 @@
+package pinata_test
+
+import (
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestMergePatch(t *testing.T) {
+	target := decode(t, `{"a": "b", "c": {"d": "e", "f": "g"}, "h": [1]}`)
+	patch := decode(t, `{"a": "z", "c": {"f": null}, "h": {"i": 2}}`)
+
+	merged := pinata.MergePatch(target, patch)
+	if expected := decode(t, `{"a": "z", "c": {"d": "e"}, "h": {"i": 2}}`); !pinata.Equal(merged, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), merged.Value())
+	}
+	if expected := decode(t, `{"a": "b", "c": {"d": "e", "f": "g"}, "h": [1]}`); !pinata.Equal(target, expected) {
+		t.Error("the target must not be modified")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	defaults := decode(t, `{"Name": "app", "DB": {"Host": "localhost", "Port": 5432}, "Tags": ["a"]}`)
+	file := decode(t, `{"DB": {"Host": "db.internal"}, "Tags": ["b"]}`)
+	env := decode(t, `{"DB": {"Port": "oops"}}`)
+
+	merged := pinata.MergeWith(pinata.MergeOptions{LayerNames: []string{"defaults", "config.json", "env overlay"}}, defaults, file, env)
+	if expected := decode(t, `{"Name": "app", "DB": {"Host": "db.internal", "Port": "oops"}, "Tags": ["b"]}`); !pinata.Equal(merged, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), merged.Value())
+	}
+
+	for path, expected := range map[string][]string{
+		"defaults":    {"Name"},
+		"config.json": {"DB", "Host"},
+		"env overlay": {"DB", "Port"},
+	} {
+		if origin, ok := merged.Origin(expected...); !ok || origin != path {
+			t.Errorf("%v must come from %q, got %q", expected, path, origin)
+		}
+	}
+	if _, ok := merged.Origin("DB", "User"); ok {
+		t.Error("a missing path must have no origin")
+	}
+
+	stick := pinata.NewStick()
+	stick.PathFloat64(merged, "DB", "Port")
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("Port must not be a float64")
+	}
+	const expected = `pinata: incompatible type (this is not a float64) at PathFloat64("DB", "Port") (from "env overlay")`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestMergeArrays(t *testing.T) {
+	a := decode(t, `{"Users": [{"id": 1, "name": "Kevin"}, {"id": 2, "name": "Bob"}]}`)
+	b := decode(t, `{"Users": [{"id": 2, "name": "Robert"}, {"id": 3, "name": "Ann"}]}`)
+
+	appended := pinata.MergeWith(pinata.MergeOptions{Arrays: pinata.ArrayAppend}, a, b)
+	if users, _ := pinata.NewStick().Path(appended, "Users").Slice(); len(users) != 4 {
+		t.Error("appending must keep all users, got", users)
+	}
+
+	merged := pinata.MergeWith(pinata.MergeOptions{Arrays: pinata.ArrayMergeByKey, ArrayKey: "id"}, a, b)
+	expected := decode(t, `{"Users": [{"id": 1, "name": "Kevin"}, {"id": 2, "name": "Robert"}, {"id": 3, "name": "Ann"}]}`)
+	if !pinata.Equal(merged, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), merged.Value())
+	}
+	for _, c := range []struct {
+		path   []string
+		origin string
+	}{
+		{[]string{"Users", "0", "name"}, "layer 1"},
+		{[]string{"Users", "1", "name"}, "layer 2"},
+		{[]string{"Users", "2", "id"}, "layer 2"},
+	} {
+		if origin, _ := merged.Origin(c.path...); origin != c.origin {
+			t.Errorf("%v must come from %q, got %q", c.path, c.origin, origin)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package pinata
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// deepCopy copies the maps and slices within v so the copy can be modified
+// without affecting v.
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k := range t {
+			m[k] = deepCopy(t[k])
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i := range t {
+			s[i] = deepCopy(t[i])
+		}
+		return s
+	}
+	return v
+}
+
+// patchFailure describes why a patch operation could not be applied.
+type patchFailure struct {
+	reason ErrorReason
+	advice string
+}
+
+func notFound(tokens []string) *patchFailure {
+	return &patchFailure{ErrorReasonNotFound, fmt.Sprintf("%q does not exist", formatPointer(tokens))}
+}
+
+// update calls fn with the container holding the value the tokens point to
+// and the last token, and stores the container fn returns in its place.
+// There must be at least one token.
+func update(node interface{}, tokens, location []string, fn func(container interface{}, token string, location []string) (interface{}, *patchFailure)) (interface{}, *patchFailure) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0], location)
+	}
+	location = appendToken(location, tokens[0])
+	switch t := node.(type) {
+	case map[string]interface{}:
+		child, ok := t[tokens[0]]
+		if !ok {
+			return nil, notFound(location)
+		}
+		child, failure := update(child, tokens[1:], location, fn)
+		if failure != nil {
+			return nil, failure
+		}
+		t[tokens[0]] = child
+		return t, nil
+	case []interface{}:
+		i, ok := arrayIndex(tokens[0], len(t))
+		if !ok {
+			return nil, notFound(location)
+		}
+		child, failure := update(t[i], tokens[1:], location, fn)
+		if failure != nil {
+			return nil, failure
+		}
+		t[i] = child
+		return t, nil
+	}
+	return nil, incompatibleContainer(location[:len(location)-1])
+}
+
+func incompatibleContainer(location []string) *patchFailure {
+	return &patchFailure{ErrorReasonIncompatibleType, fmt.Sprintf("%q is neither a map nor a slice", formatPointer(location))}
+}
+
+func patchGet(node interface{}, tokens []string) (interface{}, *patchFailure) {
+	v, ok := resolvePointer(node, tokens)
+	if !ok {
+		return nil, notFound(tokens)
+	}
+	return v, nil
+}
+
+func patchAdd(node interface{}, tokens []string, value interface{}) (interface{}, *patchFailure) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(node, tokens, nil, func(container interface{}, token string, location []string) (interface{}, *patchFailure) {
+		switch t := container.(type) {
+		case map[string]interface{}:
+			t[token] = value
+			return t, nil
+		case []interface{}:
+			i := len(t)
+			if token != "-" {
+				var ok bool
+				if i, ok = arrayIndex(token, len(t)+1); !ok {
+					return nil, &patchFailure{ErrorReasonInvalidInput, fmt.Sprintf("specify an index from 0 to %d or \"-\" for %q", len(t), formatPointer(location))}
+				}
+			}
+			t = append(t, nil)
+			copy(t[i+1:], t[i:])
+			t[i] = value
+			return t, nil
+		}
+		return nil, incompatibleContainer(location)
+	})
+}
+
+func patchRemove(node interface{}, tokens []string) (interface{}, *patchFailure) {
+	if len(tokens) == 0 {
+		return nil, &patchFailure{ErrorReasonInvalidInput, "the whole document cannot be removed"}
+	}
+	return update(node, tokens, nil, func(container interface{}, token string, location []string) (interface{}, *patchFailure) {
+		switch t := container.(type) {
+		case map[string]interface{}:
+			if _, ok := t[token]; !ok {
+				return nil, notFound(appendToken(location, token))
+			}
+			delete(t, token)
+			return t, nil
+		case []interface{}:
+			i, ok := arrayIndex(token, len(t))
+			if !ok {
+				return nil, notFound(appendToken(location, token))
+			}
+			return append(t[:i], t[i+1:]...), nil
+		}
+		return nil, incompatibleContainer(location)
+	})
+}
+
+func patchReplace(node interface{}, tokens []string, value interface{}) (interface{}, *patchFailure) {
+	if _, failure := patchGet(node, tokens); failure != nil {
+		return nil, failure
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(node, tokens, nil, func(container interface{}, token string, location []string) (interface{}, *patchFailure) {
+		switch t := container.(type) {
+		case map[string]interface{}:
+			t[token] = value
+			return t, nil
+		case []interface{}:
+			i, _ := arrayIndex(token, len(t))
+			t[i] = value
+			return t, nil
+		}
+		return nil, incompatibleContainer(location)
+	})
+}
+
+// applyOperation applies a single JSON Patch operation to doc and returns the
+// updated document.
+func applyOperation(doc interface{}, operation map[string]interface{}) (interface{}, *patchFailure) {
+	member := func(name string) (string, []string, *patchFailure) {
+		s, ok := operation[name].(string)
+		if !ok {
+			return "", nil, &patchFailure{ErrorReasonInvalidInput, fmt.Sprintf("%q must be a string", name)}
+		}
+		tokens, ok := parsePointer(s)
+		if !ok {
+			return "", nil, &patchFailure{ErrorReasonInvalidInput, fmt.Sprintf("%q is not a JSON Pointer", s)}
+		}
+		return s, tokens, nil
+	}
+	value := func() (interface{}, *patchFailure) {
+		v, ok := operation["value"]
+		if !ok {
+			return nil, &patchFailure{ErrorReasonInvalidInput, `"value" is missing`}
+		}
+		return deepCopy(v), nil
+	}
+
+	op, _ := operation["op"].(string)
+	path, tokens, failure := member("path")
+	if failure != nil {
+		return nil, failure
+	}
+	switch op {
+	case "add", "replace", "test":
+		v, failure := value()
+		if failure != nil {
+			return nil, failure
+		}
+		switch op {
+		case "add":
+			return patchAdd(doc, tokens, v)
+		case "replace":
+			return patchReplace(doc, tokens, v)
+		}
+		current, failure := patchGet(doc, tokens)
+		if failure != nil {
+			return nil, failure
+		}
+		if !equalValues(current, v) {
+			return nil, &patchFailure{ErrorReasonUnexpected, fmt.Sprintf("%q does not hold %#v", path, v)}
+		}
+		return doc, nil
+	case "remove":
+		return patchRemove(doc, tokens)
+	case "move", "copy":
+		from, fromTokens, failure := member("from")
+		if failure != nil {
+			return nil, failure
+		}
+		v, failure := patchGet(doc, fromTokens)
+		if failure != nil {
+			return nil, failure
+		}
+		if op == "copy" {
+			return patchAdd(doc, tokens, deepCopy(v))
+		}
+		if path == from {
+			return doc, nil
+		}
+		if strings.HasPrefix(path, from+"/") {
+			return nil, &patchFailure{ErrorReasonInvalidInput, fmt.Sprintf("%q cannot be moved into itself", from)}
+		}
+		if doc, failure = patchRemove(doc, fromTokens); failure != nil {
+			return nil, failure
+		}
+		return patchAdd(doc, tokens, v)
+	}
+	return nil, &patchFailure{ErrorReasonInvalidInput, fmt.Sprintf("unknown op %#v, use add, remove, replace, move, copy or test", operation["op"])}
+}
+
+// ApplyPatch applies an RFC 6902 JSON Patch to the document and returns the
+// result. The document itself is not modified. Either every operation is
+// applied or, if one fails, none are and the error is a *Error whose context
+// holds the index of the failed operation, its op and its path.
+func ApplyPatch(doc Pinata, patch Pinata) (Pinata, error) {
+	operations, ok := patch.Slice()
+	if !ok {
+		return doc, &Error{
+			context: &ErrorContext{
+				methodName: "ApplyPatch",
+				methodArgs: func() []interface{} { return nil },
+				source:     patch.source,
+				next:       patch.context,
+			},
+			reason: ErrorReasonIncompatibleType,
+			advice: "a patch must be a slice of operations",
+		}
+	}
+	result := deepCopy(doc.Value())
+	for i := range operations {
+		operation, _ := operations[i].(map[string]interface{})
+		var failure *patchFailure
+		if operation == nil {
+			failure = &patchFailure{ErrorReasonIncompatibleType, "an operation must be a map"}
+		} else {
+			result, failure = applyOperation(result, operation)
+		}
+		if failure != nil {
+			i := i
+			return doc, &Error{
+				context: &ErrorContext{
+					methodName: "ApplyPatch",
+					methodArgs: func() []interface{} { return []interface{}{i, operation["op"], operation["path"]} },
+					source:     doc.source,
+					next:       doc.context,
+				},
+				reason: failure.reason,
+				advice: failure.advice,
+			}
+		}
+	}
+	patched := newPinataWithContext(result, doc.context)
+	patched.source = doc.source
+	return patched, nil
+}
+
+// CreatePatch returns an RFC 6902 JSON Patch that turns the from Pinata into
+// the to Pinata when applied with ApplyPatch. It only uses the add, remove
+// and replace operations.
+func CreatePatch(from, to Pinata) Pinata {
+	return NewPinata(createPatch(from.Value(), to.Value(), nil, []interface{}{}))
+}
+
+func operation(op string, location []string, value ...interface{}) map[string]interface{} {
+	o := map[string]interface{}{
+		"op":   op,
+		"path": formatPointer(location),
+	}
+	if len(value) > 0 {
+		o["value"] = deepCopy(value[0])
+	}
+	return o
+}
+
+func createPatch(from, to interface{}, location []string, patch []interface{}) []interface{} {
+	if schemaType(from) != schemaType(to) {
+		return append(patch, operation("replace", location, to))
+	}
+	switch f := from.(type) {
+	case map[string]interface{}:
+		t := to.(map[string]interface{})
+		keys := make([]string, 0, len(f)+len(t))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		for k := range t {
+			if _, ok := f[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fv, inFrom := f[k]
+			tv, inTo := t[k]
+			switch {
+			case !inTo:
+				patch = append(patch, operation("remove", appendToken(location, k)))
+			case !inFrom:
+				patch = append(patch, operation("add", appendToken(location, k), tv))
+			default:
+				patch = createPatch(fv, tv, appendToken(location, k), patch)
+			}
+		}
+	case []interface{}:
+		t := to.([]interface{})
+		// skip the elements both slices start and end with, so removing or
+		// inserting an element only results in a single operation
+		prefix := 0
+		for prefix < len(f) && prefix < len(t) && equalValues(f[prefix], t[prefix]) {
+			prefix++
+		}
+		suffix := 0
+		for suffix < len(f)-prefix && suffix < len(t)-prefix && equalValues(f[len(f)-1-suffix], t[len(t)-1-suffix]) {
+			suffix++
+		}
+		fromEnd, toEnd := len(f)-suffix, len(t)-suffix
+		for i := prefix; i < fromEnd && i < toEnd; i++ {
+			patch = createPatch(f[i], t[i], appendToken(location, strconv.Itoa(i)), patch)
+		}
+		for i := fromEnd; i < toEnd; i++ {
+			patch = append(patch, operation("add", appendToken(location, strconv.Itoa(i)), t[i]))
+		}
+		for i := fromEnd - 1; i >= toEnd; i-- {
+			patch = append(patch, operation("remove", appendToken(location, strconv.Itoa(i))))
+		}
+	default:
+		if !equalValues(from, to) {
+			patch = append(patch, operation("replace", location, to))
+		}
+	}
+	return patch
+}
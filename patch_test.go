@@ -0,0 +1,113 @@
+package pinata_test
+
+import (
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestApplyPatch(t *testing.T) {
+	doc := decode(t, `{"Name": "Kevin", "Phone": ["+44"], "Address": {"City": "Gophertown"}}`)
+	patch := decode(t, `[
+		{"op": "test", "path": "/Name", "value": "Kevin"},
+		{"op": "add", "path": "/Phone/0", "value": "+32"},
+		{"op": "add", "path": "/Phone/-", "value": "+1"},
+		{"op": "replace", "path": "/Address/City", "value": "Gopherville"},
+		{"op": "copy", "from": "/Address", "path": "/Billing"},
+		{"op": "move", "from": "/Name", "path": "/FullName"},
+		{"op": "remove", "path": "/Billing/City"}
+	]`)
+	patched, err := pinata.ApplyPatch(doc, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decode(t, `{"FullName": "Kevin", "Phone": ["+32", "+44", "+1"], "Address": {"City": "Gopherville"}, "Billing": {}}`)
+	if !pinata.Equal(patched, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), patched.Value())
+	}
+
+	failing := decode(t, `[
+		{"op": "replace", "path": "/Name", "value": "Bob"},
+		{"op": "remove", "path": "/Address/Street"}
+	]`)
+	result, err := pinata.ApplyPatch(doc, failing)
+	if err == nil {
+		t.Fatal("removing a missing value must result in an error")
+	}
+	const expectedErr = `pinata: not found ("/Address/Street" does not exist) at ApplyPatch(1, "remove", "/Address/Street")`
+	if err.Error() != expectedErr {
+		t.Errorf("expected %q, got %q", expectedErr, err.Error())
+	}
+	if _, err := pinata.ApplyPatch(pinata.NewPinataFromSource("config.json", doc.Value()), failing); err.(*pinata.Error).Source() != "config.json" {
+		t.Errorf("the error must come from config.json, got %q", err.(*pinata.Error).Source())
+	}
+	if stick := pinata.NewStick(); stick.PathString(result, "Name") != "Kevin" || stick.PathString(doc, "Name") != "Kevin" {
+		t.Error("a failed patch must not be applied at all")
+	}
+
+	for _, message := range []string{
+		`[{"op": "test", "path": "/Name", "value": "Bob"}]`,
+		`[{"op": "add", "path": "/Phone/5", "value": "+1"}]`,
+		`[{"op": "move", "from": "/Address", "path": "/Address/Old"}]`,
+		`[{"op": "jump", "path": "/Name"}]`,
+		`[{"op": "add", "path": "Name", "value": 1}]`,
+		`{"op": "add", "path": "/Name", "value": 1}`,
+	} {
+		if _, err := pinata.ApplyPatch(doc, decode(t, message)); err == nil {
+			t.Errorf("%s must result in an error", message)
+		} else {
+			t.Log(err)
+		}
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	from := decode(t, `{"Name": "Kevin", "Phone": ["+44", "+32", "+1"], "Address": {"City": "Gophertown"}, "Tags": "a"}`)
+	to := decode(t, `{"Name": "Kevin", "Phone": ["+44"], "Address": {"City": "Gopherville", "Zip": "G0"}, "Tags": ["a"]}`)
+
+	patch := pinata.CreatePatch(from, to)
+	expected := decode(t, `[
+		{"op": "replace", "path": "/Address/City", "value": "Gopherville"},
+		{"op": "add", "path": "/Address/Zip", "value": "G0"},
+		{"op": "remove", "path": "/Phone/2"},
+		{"op": "remove", "path": "/Phone/1"},
+		{"op": "replace", "path": "/Tags", "value": ["a"]}
+	]`)
+	if !pinata.Equal(patch, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), patch.Value())
+	}
+
+	patched, err := pinata.ApplyPatch(from, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pinata.Equal(patched, to) {
+		t.Errorf("expected %v, got %v", to.Value(), patched.Value())
+	}
+}
+
+func TestCreatePatchSlices(t *testing.T) {
+	for _, test := range []struct {
+		from, to, expected string
+	}{
+		{`[1, 2, 3, 4, 5]`, `[2, 3, 4, 5]`, `[{"op": "remove", "path": "/0"}]`},
+		{`[1, 2, 3, 4, 5]`, `[1, 2, 4, 5]`, `[{"op": "remove", "path": "/2"}]`},
+		{`[1, 2, 3]`, `[0, 1, 2, 3]`, `[{"op": "add", "path": "/0", "value": 0}]`},
+		{`[1, 2, 3]`, `[1, 9, 8, 3]`, `[{"op": "replace", "path": "/1", "value": 9}, {"op": "add", "path": "/2", "value": 8}]`},
+		{`[1, 1]`, `[1]`, `[{"op": "remove", "path": "/1"}]`},
+		{`[1, 2, 3, 4]`, `[1, 4]`, `[{"op": "remove", "path": "/2"}, {"op": "remove", "path": "/1"}]`},
+	} {
+		from, to := decode(t, test.from), decode(t, test.to)
+		patch := pinata.CreatePatch(from, to)
+		if expected := decode(t, test.expected); !pinata.Equal(patch, expected) {
+			t.Errorf("%s to %s: expected %v, got %v", test.from, test.to, expected.Value(), patch.Value())
+		}
+		patched, err := pinata.ApplyPatch(from, patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pinata.Equal(patched, to) {
+			t.Errorf("%s to %s: patched to %v", test.from, test.to, patched.Value())
+		}
+	}
+}
@@ -16,8 +16,14 @@ package pinata
 
 import (
 	"bytes"
+	"crypto"
 	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Stick offers methods of hitting the Pinata and extracting its goodness.
@@ -31,6 +37,10 @@ type Stick interface {
 	// was cleared.
 	ClearError() error
 
+	// Coercions returns every value conversion made so far because of the
+	// rules passed to WithCoercion, in the order they happened.
+	Coercions() []Coercion
+
 	// PathString gets the string value at the given path within the Pinata. The
 	// last element in the path must be a string, the rest must be a
 	// map[string]interface{}. The input Pinata must hold a
@@ -70,6 +80,120 @@ type Stick interface {
 	// The input Pinata must hold a []interface{}.
 	IndexBool(Pinata, int) bool
 
+	// PathTime gets the time value at the given path within the Pinata. The
+	// last element in the path must be a time in the given layout, the rest
+	// must be a map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well.
+	PathTime(Pinata, string, ...string) time.Time
+
+	// Time returns the Pinata as a time if it is one. Strings are parsed using
+	// the layout (see time.Parse), which defaults to time.RFC3339 if empty.
+	// The UnixSeconds and UnixMilliseconds layouts accept numbers and numeric
+	// strings holding an epoch timestamp. A time.Time value, as decoded by
+	// some YAML and TOML packages, is accepted whatever the layout.
+	Time(Pinata, string) time.Time
+
+	// IndexTime gets the time value at the given index within the Pinata.
+	// The input Pinata must hold a []interface{}.
+	IndexTime(Pinata, string, int) time.Time
+
+	// PathDuration gets the duration value at the given path within the
+	// Pinata. The last element in the path must be a duration, the rest must
+	// be a map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well.
+	PathDuration(Pinata, ...string) time.Duration
+
+	// Duration returns the Pinata as a duration if it is one. Strings are
+	// parsed with time.ParseDuration.
+	Duration(Pinata) time.Duration
+
+	// IndexDuration gets the duration value at the given index within the
+	// Pinata. The input Pinata must hold a []interface{}.
+	IndexDuration(Pinata, int) time.Duration
+
+	// PathBytes gets the bytes at the given path within the Pinata. The last
+	// element in the path must be a base64 string, the rest must be a
+	// map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well.
+	PathBytes(Pinata, ...string) []byte
+
+	// Bytes returns the Pinata as bytes if it holds a base64 string. Both the
+	// standard and the URL safe alphabet are accepted, padded or not.
+	Bytes(Pinata) []byte
+
+	// IndexBytes gets the bytes at the given index within the Pinata.
+	// The input Pinata must hold a []interface{}.
+	IndexBytes(Pinata, int) []byte
+
+	// PathURL gets the URL at the given path within the Pinata. The
+	// last element in the path must be a URL, the rest must be a
+	// map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well. The URL must be absolute and name a
+	// host, unless it is opaque like mailto:kevin@example.com or a file URL.
+	PathURL(Pinata, ...string) *url.URL
+
+	// URL returns the Pinata as a URL if it holds one.
+	URL(Pinata) *url.URL
+
+	// IndexURL gets the URL at the given index within the Pinata.
+	// The input Pinata must hold a []interface{}.
+	IndexURL(Pinata, int) *url.URL
+
+	// PathUUID gets the UUID at the given path within the Pinata. The
+	// last element in the path must be a UUID, the rest must be a
+	// map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well.
+	PathUUID(Pinata, ...string) UUID
+
+	// UUID returns the Pinata as a UUID if it holds one in its canonical
+	// textual form, such as "123e4567-e89b-12d3-a456-426614174000".
+	UUID(Pinata) UUID
+
+	// IndexUUID gets the UUID at the given index within the Pinata.
+	// The input Pinata must hold a []interface{}.
+	IndexUUID(Pinata, int) UUID
+
+	// PathIP gets the IP address at the given path within the Pinata. The
+	// last element in the path must be an IP address, the rest must be a
+	// map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well.
+	PathIP(Pinata, ...string) netip.Addr
+
+	// IP returns the Pinata as an IP address if it holds one.
+	IP(Pinata) netip.Addr
+
+	// IndexIP gets the IP address at the given index within the Pinata.
+	// The input Pinata must hold a []interface{}.
+	IndexIP(Pinata, int) netip.Addr
+
+	// PathPrefix gets the IP prefix at the given path within the Pinata. The
+	// last element in the path must be an IP prefix, the rest must be a
+	// map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well.
+	PathPrefix(Pinata, ...string) netip.Prefix
+
+	// Prefix returns the Pinata as an IP prefix if it holds one in CIDR
+	// notation, such as "192.168.0.0/16".
+	Prefix(Pinata) netip.Prefix
+
+	// IndexPrefix gets the IP prefix at the given index within the Pinata.
+	// The input Pinata must hold a []interface{}.
+	IndexPrefix(Pinata, int) netip.Prefix
+
+	// PathEmail gets the email address at the given path within the Pinata. The
+	// last element in the path must be an email address, the rest must be a
+	// map[string]interface{}. The input Pinata must hold a
+	// map[string]interface{} as well.
+	PathEmail(Pinata, ...string) *mail.Address
+
+	// Email returns the Pinata as an email address if it holds one (see
+	// mail.ParseAddress).
+	Email(Pinata) *mail.Address
+
+	// IndexEmail gets the email address at the given index within the Pinata.
+	// The input Pinata must hold a []interface{}.
+	IndexEmail(Pinata, int) *mail.Address
+
 	// PathNil asserts nil value at the given path within the Pinata. The last
 	// element in the path must be a nil, the rest must be a
 	// map[string]interface{}. The input Pinata must hold a
@@ -91,10 +215,65 @@ type Stick interface {
 	// Index gets the Pinata value at the given index within the Pinata.
 	// The input Pinata must hold a []interface{}.
 	Index(Pinata, int) Pinata
+
+	// FirstOf gets the Pinata value at the first of the given paths that
+	// exists within the Pinata. A path leading through a value that is not a
+	// map does not exist. The input Pinata must hold a
+	// map[string]interface{}. If none of the paths exist the error lists all
+	// of them.
+	FirstOf(Pinata, ...Path) Pinata
+
+	// FirstOfString is like FirstOf but the value found must be a string.
+	FirstOfString(Pinata, ...Path) string
+
+	// FirstOfFloat64 is like FirstOf but the value found must be a float64.
+	FirstOfFloat64(Pinata, ...Path) float64
+
+	// FirstOfBool is like FirstOf but the value found must be a bool.
+	FirstOfBool(Pinata, ...Path) bool
+
+	// Check verifies the Pinata value satisfies all of the given constraints
+	// and returns the Pinata unchanged so it can be passed on to another
+	// method, for example:
+	//  stick.String(stick.Check(stick.Path(p, "Email"), pinata.MaxLen(254)))
+	Check(Pinata, ...Constraint) Pinata
+
+	// StrictObject verifies the Pinata holds no keys other than the allowed
+	// keys and returns the Pinata unchanged. The input Pinata must hold a
+	// map[string]interface{}.
+	StrictObject(Pinata, ...string) Pinata
+
+	// Switch reads the string at the given key of the Pinata and calls the
+	// variant registered for that value. The variant receives the Pinata and
+	// errors caused within it mention the variant chosen. The input Pinata
+	// must hold a map[string]interface{}. If the key is missing or holds no
+	// known variant the error lists the valid variants.
+	Switch(Pinata, string, map[string]func(Pinata))
+
+	// Unvisited lists the JSON Pointers, relative to the Pinata, of the map
+	// keys and slice indices within the Pinata that were never read through
+	// this Stick. Within an unvisited value nothing further is listed. It
+	// requires a Stick created with WithTracking.
+	Unvisited(Pinata) []string
+
+	// PathHash gets the digest of the value at the given path within the
+	// Pinata, computed with the given hash function as by Hash. The input
+	// Pinata must hold a map[string]interface{}.
+	PathHash(Pinata, crypto.Hash, ...string) []byte
+
+	// Annotate returns the Pinata with a human readable label attached. Errors
+	// caused by this Pinata or any Pinata derived from it mention the label,
+	// for example: at PathString("City") within "billing address".
+	Annotate(Pinata, string) Pinata
 }
 
 type stick struct {
-	err error
+	err        error
+	coercion   CoercionRule
+	coercions  []Coercion
+	normalize  KeyNormalizer
+	visited    map[visit]bool
+	containers map[slot]interface{}
 }
 
 func (s *stick) ClearError() error {
@@ -108,12 +287,13 @@ func (s *stick) Error() error {
 }
 
 // this method assumes s.err != nil
-func (s *stick) unsupported(errCtx *ErrorContext, methodName string, input func() []interface{}, advice string) {
+func (s *stick) unsupported(p Pinata, methodName string, input func() []interface{}, advice string) {
 	s.err = &Error{
 		context: &ErrorContext{
 			methodName: methodName,
 			methodArgs: input,
-			next:       errCtx,
+			source:     p.source,
+			next:       p.context,
 		},
 		reason: ErrorReasonIncompatibleType,
 		advice: advice,
@@ -121,12 +301,27 @@ func (s *stick) unsupported(errCtx *ErrorContext, methodName string, input func(
 }
 
 // this method assumes s.err != nil
-func (s *stick) indexUnsupported(errCtx *ErrorContext, methodName string, index int) {
+func (s *stick) invalidFormat(p Pinata, methodName string, input func() []interface{}, advice string) {
+	s.err = &Error{
+		context: &ErrorContext{
+			methodName: methodName,
+			methodArgs: input,
+			source:     p.source,
+			next:       p.context,
+		},
+		reason: ErrorReasonInvalidFormat,
+		advice: advice,
+	}
+}
+
+// this method assumes s.err != nil
+func (s *stick) indexUnsupported(p Pinata, methodName string, index int) {
 	s.err = &Error{
 		context: &ErrorContext{
 			methodName: methodName,
 			methodArgs: func() []interface{} { return []interface{}{index} },
-			next:       errCtx,
+			source:     p.source,
+			next:       p.context,
 		},
 		reason: ErrorReasonIncompatibleType,
 		advice: "call this method on a slice pinata",
@@ -134,12 +329,13 @@ func (s *stick) indexUnsupported(errCtx *ErrorContext, methodName string, index
 }
 
 // this method assumes s.err != nil
-func (s *stick) pathUnsupported(errCtx *ErrorContext, methodName string, path []string) {
+func (s *stick) pathUnsupported(p Pinata, methodName string, path []string) {
 	s.err = &Error{
 		context: &ErrorContext{
 			methodName: methodName,
 			methodArgs: func() []interface{} { return toInterfaceSlice(path) },
-			next:       errCtx,
+			source:     p.source,
+			next:       p.context,
 		},
 		reason: ErrorReasonIncompatibleType,
 		advice: "call this method on a map pinata",
@@ -148,52 +344,72 @@ func (s *stick) pathUnsupported(errCtx *ErrorContext, methodName string, path []
 
 // this method assumes s.err != nil
 func (s *stick) internalString(p Pinata, methodName string, input func() []interface{}) string {
+	p = s.unwrap(p, methodName, input)
 	if _, ok := p.Map(); ok {
-		s.unsupported(p.context, methodName, input, "this is a map")
+		s.unsupported(p, methodName, input, "this is a map")
 		return ""
 	}
 	if _, ok := p.Slice(); ok {
-		s.unsupported(p.context, methodName, input, "this is a slice")
+		s.unsupported(p, methodName, input, "this is a slice")
 		return ""
 	}
 	if v, ok := p.Value().(string); ok {
 		return v
 	}
-	s.unsupported(p.context, methodName, input, "this is not a string")
+	if v, ok := s.coerceString(p, methodName, input); ok {
+		return v
+	}
+	s.unsupported(p, methodName, input, "this is not a string")
 	return ""
 }
 
 // this method assumes s.err != nil
 func (s *stick) internalFloat64(p Pinata, methodName string, input func() []interface{}) float64 {
+	p = s.unwrap(p, methodName, input)
 	if _, ok := p.Map(); ok {
-		s.unsupported(p.context, methodName, input, "this is a map")
+		s.unsupported(p, methodName, input, "this is a map")
 		return 0
 	}
 	if _, ok := p.Slice(); ok {
-		s.unsupported(p.context, methodName, input, "this is a slice")
+		s.unsupported(p, methodName, input, "this is a slice")
 		return 0
 	}
 	if v, ok := p.Value().(float64); ok {
 		return v
 	}
-	s.unsupported(p.context, methodName, input, "this is not a float64")
+	if v, ok := s.coerceFloat64(p, methodName, input); ok {
+		return v
+	}
+	if v, ok := p.Value().(string); ok && s.coercion&CoerceFloat64 != 0 {
+		s.unsupported(p, methodName, input, fmt.Sprintf("%q is not a number", v))
+		return 0
+	}
+	s.unsupported(p, methodName, input, "this is not a float64")
 	return 0
 }
 
 // this method assumes s.err != nil
 func (s *stick) internalBool(p Pinata, methodName string, input func() []interface{}) bool {
+	p = s.unwrap(p, methodName, input)
 	if _, ok := p.Map(); ok {
-		s.unsupported(p.context, methodName, input, "this is a map")
+		s.unsupported(p, methodName, input, "this is a map")
 		return false
 	}
 	if _, ok := p.Slice(); ok {
-		s.unsupported(p.context, methodName, input, "this is a slice")
+		s.unsupported(p, methodName, input, "this is a slice")
 		return false
 	}
 	if v, ok := p.Value().(bool); ok {
 		return v
 	}
-	s.unsupported(p.context, methodName, input, "this is not a bool")
+	if v, ok := s.coerceBool(p, methodName, input); ok {
+		return v
+	}
+	if v, ok := p.Value().(string); ok && s.coercion&CoerceBool != 0 {
+		s.unsupported(p, methodName, input, fmt.Sprintf(`%q is not one of "true", "false", "1" or "0"`, v))
+		return false
+	}
+	s.unsupported(p, methodName, input, "this is not a bool")
 	return false
 }
 
@@ -203,12 +419,12 @@ func (s *stick) internalNil(p Pinata, methodName string, input func() []interfac
 		return
 	}
 	if _, ok := p.Map(); ok {
-		s.unsupported(p.context, methodName, input, "this is a map")
+		s.unsupported(p, methodName, input, "this is a map")
 	}
 	if _, ok := p.Slice(); ok {
-		s.unsupported(p.context, methodName, input, "this is a slice")
+		s.unsupported(p, methodName, input, "this is a slice")
 	}
-	s.unsupported(p.context, methodName, input, "this is not nil")
+	s.unsupported(p, methodName, input, "this is not nil")
 }
 
 func (s *stick) String(p Pinata) string {
@@ -247,6 +463,7 @@ func (s *stick) internalIndex(p Pinata, methodName string, index int) Pinata {
 				context: &ErrorContext{
 					methodName: methodName,
 					methodArgs: func() []interface{} { return []interface{}{index} },
+					source:     p.source,
 					next:       p.context,
 				},
 				reason: ErrorReasonInvalidInput,
@@ -254,13 +471,16 @@ func (s *stick) internalIndex(p Pinata, methodName string, index int) Pinata {
 			}
 			return Pinata{}
 		}
-		return newPinataWithContext(slice[index], &ErrorContext{
+		s.visitIndex(slice, index)
+		source := p.sourceOf(slice, strconv.Itoa(index), p.source)
+		return p.derive(slice[index], &ErrorContext{
 			methodName: methodName,
 			methodArgs: func() []interface{} { return []interface{}{index} },
+			source:     source,
 			next:       p.context,
-		})
+		}, source)
 	}
-	s.indexUnsupported(p.context, methodName, index)
+	s.indexUnsupported(p, methodName, index)
 	return Pinata{}
 }
 
@@ -323,77 +543,65 @@ func (s *stick) IndexNil(p Pinata, index int) {
 	s.internalNil(pinata, methodName, func() []interface{} { return []interface{}{index} })
 }
 
+// this method assumes s.err != nil
+func (s *stick) pathError(p Pinata, methodName string, path []string, reason ErrorReason, advice string) {
+	s.err = &Error{
+		context: &ErrorContext{
+			methodName: methodName,
+			methodArgs: func() []interface{} { return toInterfaceSlice(path) },
+			source:     p.source,
+			next:       p.context,
+		},
+		reason: reason,
+		advice: advice,
+	}
+}
+
 // this method assumes s.err != nil
 func (s *stick) internalPath(p Pinata, methodName string, path ...string) Pinata {
 	contents, ok := p.Map()
 
 	if !ok {
-		s.pathUnsupported(p.context, methodName, path)
+		s.pathUnsupported(p, methodName, path)
 		return Pinata{}
 	}
 
 	if len(path) == 0 {
-		s.err = &Error{
-			context: &ErrorContext{
+		s.pathError(p, methodName, path, ErrorReasonInvalidInput, "specify a path")
+		return Pinata{}
+	}
+
+	source := p.source
+	for i := range path {
+		key, ok, candidates := s.lookup(contents, path[i])
+		if len(candidates) > 1 {
+			s.pathError(p, methodName, path, ErrorReasonInvalidInput,
+				fmt.Sprintf(`"%s" is ambiguous, it matches "%s"`, strings.Join(path[:i+1], `", "`), strings.Join(candidates, `", "`)))
+			return Pinata{}
+		}
+		if !ok {
+			s.pathError(p, methodName, path, ErrorReasonNotFound,
+				fmt.Sprintf(`"%s" does not exist`, strings.Join(path[:i+1], `", "`)))
+			return Pinata{}
+		}
+		s.visitKey(contents, key)
+		source = p.sourceOf(contents, key, source)
+		v := contents[key]
+		if i == len(path)-1 {
+			return p.derive(v, &ErrorContext{
 				methodName: methodName,
 				methodArgs: func() []interface{} { return toInterfaceSlice(path) },
+				source:     source,
 				next:       p.context,
-			},
-			reason: ErrorReasonInvalidInput,
-			advice: "specify a path",
+			}, source)
 		}
-		return Pinata{}
-	}
-
-	for i := 0; i < len(path)-1; i++ {
-		current := path[i]
-		if v, ok := contents[current]; ok {
-			if v, ok := v.(map[string]interface{}); ok {
-				contents = v
-			} else {
-				s.err = &Error{
-					context: &ErrorContext{
-						methodName: methodName,
-						methodArgs: func() []interface{} { return toInterfaceSlice(path) },
-						next:       p.context,
-					},
-					reason: ErrorReasonIncompatibleType,
-					advice: fmt.Sprintf(`"%s" does not hold a pinata`, strings.Join(path[:i+1], `", "`)),
-				}
-				return Pinata{}
-			}
-		} else {
-			s.err = &Error{
-				context: &ErrorContext{
-					methodName: methodName,
-					methodArgs: func() []interface{} { return toInterfaceSlice(path) },
-					next:       p.context,
-				},
-				reason: ErrorReasonNotFound,
-				advice: fmt.Sprintf(`"%s" does not exist`, strings.Join(path[:i+1], `", "`)),
-			}
+		if contents, ok = v.(map[string]interface{}); !ok {
+			s.pathError(p, methodName, path, ErrorReasonIncompatibleType,
+				fmt.Sprintf(`"%s" does not hold a pinata`, strings.Join(path[:i+1], `", "`)))
 			return Pinata{}
 		}
 	}
-
-	if v, ok := contents[path[len(path)-1]]; ok {
-		return newPinataWithContext(v, &ErrorContext{
-			methodName: methodName,
-			methodArgs: func() []interface{} { return toInterfaceSlice(path) },
-			next:       p.context,
-		})
-	}
-
-	s.err = &Error{
-		context: &ErrorContext{
-			methodName: methodName,
-			methodArgs: func() []interface{} { return toInterfaceSlice(path) },
-			next:       p.context,
-		},
-		reason: ErrorReasonNotFound,
-		advice: fmt.Sprintf(`"%s" does not exist`, strings.Join(path, `", "`)),
-	}
-	return Pinata{}
+	panic("unreachable")
 }
 
 func (s *stick) Path(p Pinata, path ...string) Pinata {
@@ -455,12 +663,45 @@ func (s *stick) PathNil(p Pinata, path ...string) {
 	s.internalNil(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
 }
 
+func (s *stick) Annotate(p Pinata, label string) Pinata {
+	if s.err != nil {
+		return Pinata{}
+	}
+	p.context = &ErrorContext{
+		methodName: "Annotate",
+		methodArgs: func() []interface{} { return []interface{}{label} },
+		label:      label,
+		source:     p.source,
+		next:       p.context,
+	}
+	return p
+}
+
 // Pinata holds the data.
 type Pinata struct {
 	context   *ErrorContext
 	value     interface{}
 	mapFunc   func() (map[string]interface{}, bool)
 	sliceFunc func() ([]interface{}, bool)
+	source    string
+	sources   map[slot]string
+}
+
+// derive returns a Pinata holding a value found within p.
+func (p Pinata) derive(contents interface{}, context *ErrorContext, source string) Pinata {
+	child := newPinataWithContext(contents, context)
+	child.source = source
+	child.sources = p.sources
+	return child
+}
+
+// sourceOf returns the source recorded for the key within the map or slice
+// container, or fallback if there is none.
+func (p Pinata) sourceOf(container interface{}, key string, fallback string) string {
+	if source, ok := p.sources[slotOf(container, key)]; ok {
+		return source
+	}
+	return fallback
 }
 
 // Value returns the raw Pinata value.
@@ -468,6 +709,13 @@ func (p Pinata) Value() interface{} {
 	return p.value
 }
 
+// Source returns the name of the source the Pinata value came from, as given
+// to NewPinataFromSource or recorded by Merge, or an empty string if it is
+// unknown.
+func (p Pinata) Source() string {
+	return p.source
+}
+
 // Map returns the Pinata value as a map if it is one (the bool indicates
 // success).
 func (p Pinata) Map() (map[string]interface{}, bool) {
@@ -492,9 +740,16 @@ func New(contents interface{}) (Stick, Pinata) {
 	return NewStick(), NewPinata(contents)
 }
 
+// Option configures a Stick created by NewStick.
+type Option func(*stick)
+
 // NewStick returns a new Stick to hit a Pinata with.
-func NewStick() Stick {
-	return &stick{}
+func NewStick(options ...Option) Stick {
+	s := &stick{}
+	for _, option := range options {
+		option(s)
+	}
+	return s
 }
 
 // NewPinata creates a new Pinata holding the specified value.
@@ -502,6 +757,15 @@ func NewPinata(contents interface{}) Pinata {
 	return newPinataWithContext(contents, nil)
 }
 
+// NewPinataFromSource creates a new Pinata holding the specified value, which
+// came from the named source such as a file path, "env" or "flags". Every
+// Pinata derived from it and every error it causes reports the source.
+func NewPinataFromSource(source string, contents interface{}) Pinata {
+	p := newPinataWithContext(contents, nil)
+	p.source = source
+	return p
+}
+
 func noMap() (map[string]interface{}, bool) { return nil, false }
 func noSlice() ([]interface{}, bool)        { return nil, false }
 
@@ -545,12 +809,20 @@ const (
 	ErrorReasonNotFound = "not found"
 	// ErrorReasonInvalidInput indicates the input is not in the expected range or format.
 	ErrorReasonInvalidInput = "invalid input"
+	// ErrorReasonInvalidFormat indicates the contents of the Pinata could not be parsed in the expected format.
+	ErrorReasonInvalidFormat = "invalid format"
+	// ErrorReasonConstraint indicates the contents of the Pinata violate a constraint.
+	ErrorReasonConstraint = "constraint violated"
+	// ErrorReasonUnexpected indicates the Pinata holds something it is not expected to hold.
+	ErrorReasonUnexpected = "unexpected"
 )
 
 // ErrorContext contains information about the circumstances of an error.
 type ErrorContext struct {
 	methodName string
 	methodArgs func() []interface{}
+	label      string
+	source     string
 	next       *ErrorContext
 }
 
@@ -564,6 +836,18 @@ func (ec ErrorContext) MethodArgs() []interface{} {
 	return ec.methodArgs()
 }
 
+// Label returns the label if this context was added by Stick.Annotate (the
+// bool indicates success).
+func (ec ErrorContext) Label() (string, bool) {
+	return ec.label, ec.label != ""
+}
+
+// Source returns the name of the source of the value involved, or an empty
+// string if it is unknown.
+func (ec ErrorContext) Source() string {
+	return ec.source
+}
+
 // Next gets additional context linked to this one.
 func (ec ErrorContext) Next() (ErrorContext, bool) {
 	if ec.next != nil {
@@ -592,6 +876,17 @@ func (p Error) Context() (ErrorContext, bool) {
 	return ErrorContext{}, false
 }
 
+// Source returns the name of the source of the value that caused the error,
+// or an empty string if it is unknown.
+func (p Error) Source() string {
+	for current := p.context; current != nil; current = current.next {
+		if current.source != "" {
+			return current.source
+		}
+	}
+	return ""
+}
+
 // Advice contains a human readable hint detailing how to remedy this error.
 func (p Error) Advice() string {
 	return p.advice
@@ -599,30 +894,36 @@ func (p Error) Advice() string {
 
 // Error returns a summary of the problem.
 func (p Error) Error() string {
-	var summaries []string
+	var buf bytes.Buffer
 	current := p.context
 	for current != nil {
+		if label, ok := current.Label(); ok {
+			_, _ = fmt.Fprintf(&buf, " within %q", label)
+			current = current.next
+			continue
+		}
+		_, _ = buf.WriteString(" at ")
 		var methodArgs = current.MethodArgs()
-		var summary string
 		if len(methodArgs) > 0 {
-			var buf bytes.Buffer
 			_, _ = buf.WriteString(current.MethodName())
 			_ = buf.WriteByte('(')
 			for i := range methodArgs {
-				_, _ = buf.WriteString("%#v")
+				_, _ = fmt.Fprintf(&buf, "%#v", methodArgs[i])
 				if i < len(methodArgs)-1 {
 					_, _ = buf.WriteString(", ")
 				}
 			}
 			_ = buf.WriteByte(')')
-			summary = fmt.Sprintf(buf.String(), methodArgs...)
-			summaries = append(summaries, summary)
 		} else {
-			summaries = append(summaries, current.MethodName()+"()")
+			_, _ = buf.WriteString(current.MethodName() + "()")
+		}
+		// mention the source where it changes rather than at every step
+		if current.source != "" && (current.next == nil || current.next.source != current.source) {
+			_, _ = fmt.Fprintf(&buf, " (from %q)", current.source)
 		}
 		current = current.next
 	}
-	return fmt.Sprintf("pinata: %s (%s) at %v", p.Reason(), p.Advice(), strings.Join(summaries, " at "))
+	return fmt.Sprintf("pinata: %s (%s)%s", p.Reason(), p.Advice(), buf.String())
 }
 
 func toInterfaceSlice(c []string) []interface{} {
@@ -416,3 +416,71 @@ func TestNullvsAbsent(t *testing.T) {
 		t.Error("non-existent path must result in an error")
 	}
 }
+
+func TestAnnotate(t *testing.T) {
+	stick, thePinata := start(t)
+
+	address := stick.Annotate(stick.Path(thePinata, "Address"), "billing address")
+	stick.PathString(address, "City")
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("null city must not be a string")
+	}
+	const expected = `pinata: incompatible type (this is not a string) at PathString("City") within "billing address" at Path("Address")`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	ctx, _ := err.(*pinata.Error).Context()
+	if _, ok := ctx.Label(); ok {
+		t.Error("method context must not have a label")
+	}
+	ctx, _ = ctx.Next()
+	if label, ok := ctx.Label(); !ok || label != "billing address" {
+		t.Error("annotation context must have the label")
+	}
+}
+
+func TestSource(t *testing.T) {
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"DB": {"Host": "localhost", "Port": 5432}}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	file := pinata.NewPinataFromSource("config.json", m)
+	env := pinata.NewPinataFromSource("env", map[string]interface{}{
+		"DB": map[string]interface{}{"Port": "x"},
+	})
+
+	stick := pinata.NewStick()
+	if source := stick.Path(file, "DB").Source(); source != "config.json" {
+		t.Errorf("derived pinata must have source config.json, got %q", source)
+	}
+
+	stick.PathString(stick.Path(file, "DB"), "Port")
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("Port must not be a string")
+	}
+	if source := err.(*pinata.Error).Source(); source != "config.json" {
+		t.Errorf("error must have source config.json, got %q", source)
+	}
+	const expected = `pinata: incompatible type (this is not a string) at PathString("Port") at Path("DB") (from "config.json")`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	merged := pinata.Merge(file, env)
+	db := stick.Path(merged, "DB")
+	if source := stick.Path(db, "Host").Source(); source != "config.json" {
+		t.Errorf("Host must come from config.json, got %q", source)
+	}
+	stick.PathFloat64(db, "Port")
+	err = stick.ClearError()
+	if err == nil {
+		t.Fatal("Port must not be a float64")
+	}
+	if source := err.(*pinata.Error).Source(); source != "env" {
+		t.Errorf("error must have source env, got %q", source)
+	}
+	t.Log(err)
+}
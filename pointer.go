@@ -0,0 +1,45 @@
+package pinata
+
+import (
+	"strings"
+)
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// formatPointer returns the RFC 6901 JSON Pointer for the given reference
+// tokens.
+func formatPointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		_ = b.WriteByte('/')
+		_, _ = b.WriteString(pointerEscaper.Replace(token))
+	}
+	return b.String()
+}
+
+// parsePointer returns the reference tokens of an RFC 6901 JSON Pointer (the
+// bool indicates success).
+func parsePointer(pointer string) ([]string, bool) {
+	if pointer == "" {
+		return nil, true
+	}
+	if pointer[0] != '/' {
+		return nil, false
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i := range tokens {
+		tokens[i] = pointerUnescaper.Replace(tokens[i])
+	}
+	return tokens, true
+}
+
+// appendToken returns a copy of tokens with token appended, so that the
+// result can be kept while tokens is reused.
+func appendToken(tokens []string, token string) []string {
+	result := make([]string, len(tokens), len(tokens)+1)
+	copy(result, tokens)
+	return append(result, token)
+}
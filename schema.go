@@ -0,0 +1,582 @@
+package pinata
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	root *schemaNode
+}
+
+type schemaNode struct {
+	always               *bool
+	types                []string
+	properties           map[string]*schemaNode
+	required             []string
+	additionalProperties *schemaNode
+	items                *schemaNode
+	enum                 []interface{}
+	hasConst             bool
+	constValue           interface{}
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minLength            *int
+	maxLength            *int
+	minItems             *int
+	maxItems             *int
+	pattern              *regexp.Regexp
+	allOf                []*schemaNode
+	anyOf                []*schemaNode
+	oneOf                []*schemaNode
+	ref                  string
+	refNode              *schemaNode
+	location             []string
+}
+
+type schemaCompiler struct {
+	document interface{}
+	nodes    map[string]*schemaNode
+	refs     []*schemaNode
+}
+
+func compileError(location []string, advice string) *Error {
+	pointer := formatPointer(location)
+	return &Error{
+		context: &ErrorContext{
+			methodName: "CompileSchema",
+			methodArgs: func() []interface{} { return []interface{}{pointer} },
+		},
+		reason: ErrorReasonInvalidInput,
+		advice: advice,
+	}
+}
+
+// CompileSchema compiles the JSON Schema held by the Pinata. It supports the
+// following subset of draft 2020-12: type, properties, required,
+// additionalProperties, items, enum, const, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength, minItems,
+// maxItems, pattern, allOf, anyOf, oneOf and $ref pointing within the same
+// document. Other keywords are ignored. Patterns use the syntax of the regexp
+// package.
+//
+// The error is a *Error if the schema is invalid, including when it refers to
+// itself without descending into the value, such as {"$ref": "#"}.
+func CompileSchema(p Pinata) (*Schema, error) {
+	c := &schemaCompiler{
+		document: p.Value(),
+		nodes:    make(map[string]*schemaNode),
+	}
+	root, err := c.compile(p.Value(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for len(c.refs) > 0 {
+		n := c.refs[0]
+		c.refs = c.refs[1:]
+		tokens, ok := parsePointer(strings.TrimPrefix(n.ref, "#"))
+		if !strings.HasPrefix(n.ref, "#") || !ok {
+			return nil, compileError(nil, fmt.Sprintf("$ref %q must point within the schema, for example \"#/$defs/name\"", n.ref))
+		}
+		target, ok := resolvePointer(c.document, tokens)
+		if !ok {
+			return nil, compileError(tokens, fmt.Sprintf("$ref %q does not exist", n.ref))
+		}
+		if n.refNode, err = c.compile(target, tokens); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.checkCycles(); err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+// checkCycles returns an error if a schema reaches itself through $ref,
+// allOf, anyOf or oneOf alone, such as {"$ref": "#"}, because validating it
+// would apply it to the same value forever. Cycles through keywords that
+// descend into the value, such as items, end with the value and are fine.
+func (c *schemaCompiler) checkCycles() error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[*schemaNode]int, len(c.nodes))
+	var visit func(n *schemaNode) error
+	visit = func(n *schemaNode) error {
+		switch state[n] {
+		case visiting:
+			return compileError(n.location, "the schema refers to itself without descending into the value")
+		case visited:
+			return nil
+		}
+		state[n] = visiting
+		next := append(append(append([]*schemaNode(nil), n.allOf...), n.anyOf...), n.oneOf...)
+		if n.refNode != nil {
+			next = append(next, n.refNode)
+		}
+		for _, sub := range next {
+			if err := visit(sub); err != nil {
+				return err
+			}
+		}
+		state[n] = visited
+		return nil
+	}
+
+	// visit in a fixed order so the same cycle is always reported
+	keys := make([]string, 0, len(c.nodes))
+	for key := range c.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := visit(c.nodes[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolvePointer finds the value the reference tokens point to (the bool
+// indicates success).
+func resolvePointer(document interface{}, tokens []string) (interface{}, bool) {
+	current := document
+	for _, token := range tokens {
+		switch t := current.(type) {
+		case map[string]interface{}:
+			v, ok := t[token]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []interface{}:
+			i, ok := arrayIndex(token, len(t))
+			if !ok {
+				return nil, false
+			}
+			current = t[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// arrayIndex parses a JSON Pointer reference token as an index into an array
+// of the given length (the bool indicates success).
+func arrayIndex(token string, length int) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	i := 0
+	for _, r := range token {
+		if r < '0' || r > '9' || i > length {
+			return 0, false
+		}
+		i = i*10 + int(r-'0')
+	}
+	return i, i < length
+}
+
+func (c *schemaCompiler) compile(raw interface{}, location []string) (*schemaNode, error) {
+	key := formatPointer(location)
+	if n, ok := c.nodes[key]; ok {
+		return n, nil
+	}
+	n := &schemaNode{location: location}
+	c.nodes[key] = n
+
+	if b, ok := raw.(bool); ok {
+		n.always = &b
+		return n, nil
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, compileError(location, "a schema must be an object or a bool")
+	}
+
+	var err error
+	sub := func(keyword string) (*schemaNode, error) {
+		v, ok := m[keyword]
+		if !ok {
+			return nil, nil
+		}
+		return c.compile(v, appendToken(location, keyword))
+	}
+	subs := func(keyword string) ([]*schemaNode, error) {
+		v, ok := m[keyword]
+		if !ok {
+			return nil, nil
+		}
+		list, ok := v.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, compileError(appendToken(location, keyword), fmt.Sprintf("%q must be a non-empty array of schemas", keyword))
+		}
+		nodes := make([]*schemaNode, len(list))
+		for i := range list {
+			if nodes[i], err = c.compile(list[i], appendToken(appendToken(location, keyword), fmt.Sprint(i))); err != nil {
+				return nil, err
+			}
+		}
+		return nodes, nil
+	}
+	float := func(keyword string) (*float64, error) {
+		v, ok := m[keyword]
+		if !ok {
+			return nil, nil
+		}
+		f, ok := number(v)
+		if !ok {
+			return nil, compileError(appendToken(location, keyword), fmt.Sprintf("%q must be a number", keyword))
+		}
+		return &f, nil
+	}
+	count := func(keyword string) (*int, error) {
+		v, ok := m[keyword]
+		if !ok {
+			return nil, nil
+		}
+		f, ok := number(v)
+		if !ok || f < 0 || f != math.Trunc(f) {
+			return nil, compileError(appendToken(location, keyword), fmt.Sprintf("%q must be a non-negative integer", keyword))
+		}
+		i := int(f)
+		return &i, nil
+	}
+
+	if v, ok := m["type"]; ok {
+		switch t := v.(type) {
+		case string:
+			n.types = []string{t}
+		case []interface{}:
+			for i := range t {
+				s, ok := t[i].(string)
+				if !ok {
+					return nil, compileError(appendToken(location, "type"), `"type" must be a string or an array of strings`)
+				}
+				n.types = append(n.types, s)
+			}
+		default:
+			return nil, compileError(appendToken(location, "type"), `"type" must be a string or an array of strings`)
+		}
+		for _, t := range n.types {
+			switch t {
+			case "null", "boolean", "object", "array", "number", "string", "integer":
+			default:
+				return nil, compileError(appendToken(location, "type"), fmt.Sprintf("unknown type %q", t))
+			}
+		}
+	}
+
+	if v, ok := m["properties"]; ok {
+		props, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, compileError(appendToken(location, "properties"), `"properties" must be an object`)
+		}
+		n.properties = make(map[string]*schemaNode, len(props))
+		for name, raw := range props {
+			if n.properties[name], err = c.compile(raw, appendToken(appendToken(location, "properties"), name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if v, ok := m["required"]; ok {
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, compileError(appendToken(location, "required"), `"required" must be an array of strings`)
+		}
+		for i := range list {
+			s, ok := list[i].(string)
+			if !ok {
+				return nil, compileError(appendToken(location, "required"), `"required" must be an array of strings`)
+			}
+			n.required = append(n.required, s)
+		}
+	}
+
+	if v, ok := m["enum"]; ok {
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, compileError(appendToken(location, "enum"), `"enum" must be an array`)
+		}
+		n.enum = list
+	}
+	n.constValue, n.hasConst = m["const"]
+
+	if v, ok := m["pattern"]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return nil, compileError(appendToken(location, "pattern"), `"pattern" must be a string`)
+		}
+		if n.pattern, err = regexp.Compile(s); err != nil {
+			return nil, compileError(appendToken(location, "pattern"), fmt.Sprintf("invalid pattern: %s", err))
+		}
+	}
+
+	if v, ok := m["$ref"]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return nil, compileError(appendToken(location, "$ref"), `"$ref" must be a string`)
+		}
+		n.ref = s
+		c.refs = append(c.refs, n)
+	}
+
+	if n.additionalProperties, err = sub("additionalProperties"); err != nil {
+		return nil, err
+	}
+	if n.items, err = sub("items"); err != nil {
+		return nil, err
+	}
+	if n.allOf, err = subs("allOf"); err != nil {
+		return nil, err
+	}
+	if n.anyOf, err = subs("anyOf"); err != nil {
+		return nil, err
+	}
+	if n.oneOf, err = subs("oneOf"); err != nil {
+		return nil, err
+	}
+	if n.minimum, err = float("minimum"); err != nil {
+		return nil, err
+	}
+	if n.maximum, err = float("maximum"); err != nil {
+		return nil, err
+	}
+	if n.exclusiveMinimum, err = float("exclusiveMinimum"); err != nil {
+		return nil, err
+	}
+	if n.exclusiveMaximum, err = float("exclusiveMaximum"); err != nil {
+		return nil, err
+	}
+	if n.minLength, err = count("minLength"); err != nil {
+		return nil, err
+	}
+	if n.maxLength, err = count("maxLength"); err != nil {
+		return nil, err
+	}
+	if n.minItems, err = count("minItems"); err != nil {
+		return nil, err
+	}
+	if n.maxItems, err = count("maxItems"); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Validate checks the Pinata against the schema and returns every violation
+// found, or nil if there are none. Each violation has ErrorReasonConstraint as
+// its reason and a context with the method name "Validate" whose argument is
+// the JSON Pointer of the offending value. The source of each violation is the
+// source of the offending value, such as the layer of a merged Pinata it came
+// from.
+func (s *Schema) Validate(p Pinata) []*Error {
+	v := validation{pinata: p}
+	v.validate(s.root, p.Value(), nil, p.source)
+	return v.errs
+}
+
+type validation struct {
+	// pinata is the validated Pinata, holding the context and the sources
+	pinata Pinata
+	errs   []*Error
+}
+
+func (v *validation) fail(location []string, source string, format string, args ...interface{}) {
+	pointer := formatPointer(location)
+	v.errs = append(v.errs, &Error{
+		context: &ErrorContext{
+			methodName: "Validate",
+			methodArgs: func() []interface{} { return []interface{}{pointer} },
+			source:     source,
+			next:       v.pinata.context,
+		},
+		reason: ErrorReasonConstraint,
+		advice: fmt.Sprintf(format, args...),
+	})
+}
+
+// matches reports whether value is valid against n without keeping errors.
+func (v *validation) matches(n *schemaNode, value interface{}, location []string, source string) bool {
+	sub := validation{pinata: v.pinata}
+	sub.validate(n, value, location, source)
+	return len(sub.errs) == 0
+}
+
+func schemaType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if _, ok := number(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func hasType(value interface{}, t string) bool {
+	actual := schemaType(value)
+	if t == "integer" && actual == "number" {
+		f, _ := number(value)
+		return f == math.Trunc(f) && !math.IsInf(f, 0)
+	}
+	return actual == t
+}
+
+func (v *validation) validate(n *schemaNode, value interface{}, location []string, source string) {
+	if n.always != nil {
+		if !*n.always {
+			v.fail(location, source, "no value is allowed here")
+		}
+		return
+	}
+
+	if n.refNode != nil {
+		v.validate(n.refNode, value, location, source)
+	}
+
+	if len(n.types) > 0 {
+		ok := false
+		for _, t := range n.types {
+			if hasType(value, t) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			v.fail(location, source, "must be of type %s, not %s", strings.Join(quoteAll(n.types), " or "), schemaType(value))
+			return
+		}
+	}
+
+	if n.enum != nil {
+		ok := false
+		for _, e := range n.enum {
+			if equalValues(value, e) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			v.fail(location, source, "must be one of the values in the enum")
+		}
+	}
+	if n.hasConst && !equalValues(value, n.constValue) {
+		v.fail(location, source, "must be equal to %#v", n.constValue)
+	}
+
+	if f, ok := number(value); ok {
+		if n.minimum != nil && f < *n.minimum {
+			v.fail(location, source, "must be at least %v", *n.minimum)
+		}
+		if n.maximum != nil && f > *n.maximum {
+			v.fail(location, source, "must be at most %v", *n.maximum)
+		}
+		if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+			v.fail(location, source, "must be greater than %v", *n.exclusiveMinimum)
+		}
+		if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+			v.fail(location, source, "must be less than %v", *n.exclusiveMaximum)
+		}
+	}
+
+	switch t := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(t)
+		if n.minLength != nil && length < *n.minLength {
+			v.fail(location, source, "must be at least %d characters long", *n.minLength)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			v.fail(location, source, "must be at most %d characters long", *n.maxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(t) {
+			v.fail(location, source, "must match %q", n.pattern.String())
+		}
+	case []interface{}:
+		if n.minItems != nil && len(t) < *n.minItems {
+			v.fail(location, source, "must have at least %d items", *n.minItems)
+		}
+		if n.maxItems != nil && len(t) > *n.maxItems {
+			v.fail(location, source, "must have at most %d items", *n.maxItems)
+		}
+		if n.items != nil {
+			for i := range t {
+				v.validate(n.items, t[i], appendToken(location, fmt.Sprint(i)), v.pinata.sourceOf(t, fmt.Sprint(i), source))
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range n.required {
+			if _, ok := t[name]; !ok {
+				v.fail(location, source, "required property %q is missing", name)
+			}
+		}
+		names := make([]string, 0, len(t))
+		for name := range t {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := n.properties[name]; ok {
+				v.validate(prop, t[name], appendToken(location, name), v.pinata.sourceOf(t, name, source))
+			} else if n.additionalProperties != nil {
+				if n.additionalProperties.always != nil && !*n.additionalProperties.always {
+					v.fail(appendToken(location, name), v.pinata.sourceOf(t, name, source), "property %q is not allowed", name)
+					continue
+				}
+				v.validate(n.additionalProperties, t[name], appendToken(location, name), v.pinata.sourceOf(t, name, source))
+			}
+		}
+	}
+
+	for _, sub := range n.allOf {
+		v.validate(sub, value, location, source)
+	}
+	if n.anyOf != nil {
+		ok := false
+		for _, sub := range n.anyOf {
+			if v.matches(sub, value, location, source) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			v.fail(location, source, "must be valid against at least one schema in anyOf")
+		}
+	}
+	if n.oneOf != nil {
+		matched := 0
+		for _, sub := range n.oneOf {
+			if v.matches(sub, value, location, source) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.fail(location, source, "must be valid against exactly one schema in oneOf, not %d", matched)
+		}
+	}
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i := range values {
+		quoted[i] = fmt.Sprintf("%q", values[i])
+	}
+	return quoted
+}
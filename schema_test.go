@@ -0,0 +1,171 @@
+package pinata_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func decode(t *testing.T, message string) pinata.Pinata {
+	var v interface{}
+	if err := json.Unmarshal([]byte(message), &v); err != nil {
+		t.Fatal(err)
+	}
+	return pinata.NewPinata(v)
+}
+
+func TestSchema(t *testing.T) {
+	schema, err := pinata.CompileSchema(decode(t, `
+	{
+		"type": "object",
+		"required": ["Name", "Phone", "Address"],
+		"additionalProperties": false,
+		"properties": {
+			"Name": {"type": "string", "minLength": 1, "pattern": "^[A-Z]"},
+			"Phone": {"type": "array", "maxItems": 3, "items": {"type": "string"}},
+			"Address": {"$ref": "#/$defs/address"},
+			"Hobbies": {"type": "array"},
+			"Age": {"type": "integer", "minimum": 0}
+		},
+		"$defs": {
+			"address": {
+				"type": "object",
+				"required": ["Street", "City"],
+				"properties": {
+					"Street": {"type": "string"},
+					"City": {"oneOf": [{"type": "null"}, {"enum": ["Gophertown", "Gopherville"]}]}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, thePinata := start(t)
+	if errs := schema.Validate(thePinata); len(errs) != 0 {
+		t.Fatal("Kevin must be valid", errs)
+	}
+
+	errs := schema.Validate(decode(t, `
+	{
+		"Name": "kevin",
+		"Phone": [44],
+		"Address": {"Street": "1 Gopher Road", "City": "Nowhere"},
+		"Age": 1.5,
+		"Nickname": "Kev"
+	}`))
+	expected := map[string]bool{
+		`pinata: constraint violated (must match "^[A-Z]") at Validate("/Name")`:                                              true,
+		`pinata: constraint violated (must be of type "string", not number) at Validate("/Phone/0")`:                          true,
+		`pinata: constraint violated (must be valid against exactly one schema in oneOf, not 0) at Validate("/Address/City")`: true,
+		`pinata: constraint violated (must be of type "integer", not number) at Validate("/Age")`:                             true,
+		`pinata: constraint violated (property "Nickname" is not allowed) at Validate("/Nickname")`:                           true,
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("expected %d errors, got %d", len(expected), len(errs))
+	}
+	for _, err := range errs {
+		if !expected[err.Error()] {
+			t.Error("unexpected error", err)
+		}
+	}
+
+	if _, err := pinata.CompileSchema(decode(t, `{"$ref": "#/$defs/nope"}`)); err == nil {
+		t.Error("a dangling $ref must not compile")
+	} else {
+		t.Log(err)
+	}
+	if _, err := pinata.CompileSchema(decode(t, `{"minimum": "zero"}`)); err == nil {
+		t.Error("a non-numeric minimum must not compile")
+	} else {
+		t.Log(err)
+	}
+}
+
+func TestSchemaSources(t *testing.T) {
+	schema, err := pinata.CompileSchema(decode(t, `
+	{
+		"type": "object",
+		"properties": {
+			"DB": {
+				"type": "object",
+				"properties": {
+					"Host": {"type": "string"},
+					"Port": {"type": "integer"}
+				}
+			},
+			"Tags": {"type": "array", "items": {"type": "string"}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := pinata.NewPinataFromSource("config.json", decode(t, `{"DB": {"Host": 1}, "Tags": [2]}`).Value())
+	env := pinata.NewPinataFromSource("env", decode(t, `{"DB": {"Port": "oops"}}`).Value())
+	expected := map[string]string{
+		"/DB/Host": "config.json",
+		"/DB/Port": "env",
+		"/Tags/0":  "config.json",
+	}
+	errs := schema.Validate(pinata.Merge(file, env))
+	if len(errs) != len(expected) {
+		t.Fatal("expected 3 errors, got", errs)
+	}
+	for _, err := range errs {
+		ctx, _ := err.Context()
+		location := ctx.MethodArgs()[0].(string)
+		if source := err.Source(); source != expected[location] {
+			t.Errorf("%s must come from %q, got %q", location, expected[location], source)
+		}
+	}
+
+	errs = schema.Validate(pinata.NewPinataFromSource("config.json", 1))
+	if len(errs) != 1 || errs[0].Source() != "config.json" {
+		t.Error("an invalid root must come from config.json, got", errs)
+	}
+}
+
+func TestSchemaRecursiveRef(t *testing.T) {
+	schema, err := pinata.CompileSchema(decode(t, `
+	{
+		"type": "object",
+		"properties": {
+			"Name": {"type": "string"},
+			"Children": {"type": "array", "items": {"$ref": "#"}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := schema.Validate(decode(t, `{"Name": "a", "Children": [{"Name": "b", "Children": [{"Name": 3}]}]}`))
+	if len(errs) != 1 {
+		t.Fatal("expected 1 error, got", errs)
+	}
+	ctx, _ := errs[0].Context()
+	if ctx.MethodArgs()[0] != "/Children/0/Children/0/Name" {
+		t.Error("unexpected location", ctx.MethodArgs()[0])
+	}
+}
+
+func TestSchemaRefCycle(t *testing.T) {
+	for _, message := range []string{
+		`{"$ref": "#"}`,
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`,
+		`{"allOf": [{"type": "object"}, {"$ref": "#"}]}`,
+		`{"properties": {"a": {"anyOf": [{"$ref": "#/properties/a"}]}}}`,
+	} {
+		_, err := pinata.CompileSchema(decode(t, message))
+		if err == nil {
+			t.Errorf("%s must not compile", message)
+			continue
+		}
+		if err, ok := err.(*pinata.Error); !ok || err.Reason() != pinata.ErrorReasonInvalidInput {
+			t.Errorf("%s must result in an invalid input *pinata.Error, got %v", message, err)
+		} else {
+			t.Log(err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package pinata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// closest returns the allowed key most similar to key, if any is similar
+// enough to be a likely misspelling (the bool indicates success).
+func closest(key string, allowed []string) (string, bool) {
+	best, bestDistance := "", len([]rune(key))/2+1
+	for _, a := range allowed {
+		if d := distance(strings.ToLower(key), strings.ToLower(a)); d < bestDistance {
+			best, bestDistance = a, d
+		}
+	}
+	return best, best != ""
+}
+
+func (s *stick) StrictObject(p Pinata, allowedKeys ...string) Pinata {
+	if s.err != nil {
+		return Pinata{}
+	}
+	const methodName = "StrictObject"
+	contents, ok := p.Map()
+	if !ok {
+		s.pathUnsupported(p, methodName, allowedKeys)
+		return Pinata{}
+	}
+
+	allowed := make(map[string]bool, len(allowedKeys))
+	for _, k := range allowedKeys {
+		allowed[k] = true
+		if s.normalize != nil {
+			allowed[s.normalize(k)] = true
+		}
+	}
+	var unexpected []string
+	for k := range contents {
+		if allowed[k] || (s.normalize != nil && allowed[s.normalize(k)]) {
+			continue
+		}
+		unexpected = append(unexpected, k)
+	}
+	if len(unexpected) == 0 {
+		return p
+	}
+
+	sort.Strings(unexpected)
+	descriptions := make([]string, len(unexpected))
+	for i, k := range unexpected {
+		descriptions[i] = fmt.Sprintf("%q", k)
+		if suggestion, ok := closest(k, allowedKeys); ok {
+			descriptions[i] += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+	}
+	advice := "unexpected key " + descriptions[0]
+	if len(descriptions) > 1 {
+		advice = "unexpected keys " + strings.Join(descriptions, ", ")
+	}
+	s.err = &Error{
+		context: &ErrorContext{
+			methodName: methodName,
+			methodArgs: func() []interface{} { return toInterfaceSlice(allowedKeys) },
+			source:     p.source,
+			next:       p.context,
+		},
+		reason: ErrorReasonUnexpected,
+		advice: advice,
+	}
+	return Pinata{}
+}
@@ -0,0 +1,43 @@
+package pinata_test
+
+import (
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestStrictObject(t *testing.T) {
+	stick, thePinata := start(t)
+
+	address := stick.StrictObject(stick.Path(thePinata, "Address"), "Street", "City", "Zip")
+	if v := stick.PathString(address, "Street"); v != "1 Gopher Road" {
+		t.Error("StrictObject must return the Pinata unchanged, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	stick.StrictObject(thePinata, "Name", "Phones", "Adress")
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("unexpected keys must result in an error")
+	}
+	if err.(*pinata.Error).Reason() != pinata.ErrorReasonUnexpected {
+		t.Error("error reason must be unexpected")
+	}
+	const expected = `pinata: unexpected (unexpected keys "Address" (did you mean "Adress"?), "Hobbies", "Phone" (did you mean "Phones"?)) at StrictObject("Name", "Phones", "Adress")`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	stick.StrictObject(stick.Path(thePinata, "Phone"), "Name")
+	if err := stick.ClearError(); err == nil {
+		t.Error("a slice pinata must result in an error")
+	}
+
+	normalizing := pinata.NewStick(pinata.WithKeyNormalizer(pinata.IgnoreCase))
+	normalizing.StrictObject(thePinata, "name", "phone", "address", "hobbies")
+	if err := normalizing.ClearError(); err != nil {
+		t.Error("keys must be matched with the key normalizer", err)
+	}
+}
@@ -0,0 +1,55 @@
+package pinata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func (s *stick) Switch(p Pinata, key string, variants map[string]func(Pinata)) {
+	if s.err != nil {
+		return
+	}
+	const methodName = "Switch"
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	valid := fmt.Sprintf(`"%s"`, strings.Join(names, `", "`))
+
+	pinata := s.internalPath(p, methodName, key)
+	if err, ok := s.err.(*Error); ok {
+		if err.reason == ErrorReasonNotFound {
+			err.advice += fmt.Sprintf(", it must be one of %s", valid)
+		}
+		return
+	}
+	pinata.context = p.context
+	name := s.internalString(pinata, methodName, func() []interface{} { return []interface{}{key} })
+	if s.err != nil {
+		return
+	}
+
+	variant, ok := variants[name]
+	if !ok {
+		s.err = &Error{
+			context: &ErrorContext{
+				methodName: methodName,
+				methodArgs: func() []interface{} { return []interface{}{key} },
+				source:     p.source,
+				next:       p.context,
+			},
+			reason: ErrorReasonUnexpected,
+			advice: fmt.Sprintf("%q is not a known variant, it must be one of %s", name, valid),
+		}
+		return
+	}
+	p.context = &ErrorContext{
+		methodName: methodName,
+		methodArgs: func() []interface{} { return []interface{}{key, name} },
+		source:     p.source,
+		next:       p.context,
+	}
+	variant(p)
+}
@@ -0,0 +1,46 @@
+package pinata_test
+
+import (
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestSwitch(t *testing.T) {
+	stick := pinata.NewStick()
+
+	var card, iban string
+	variants := map[string]func(pinata.Pinata){
+		"card": func(p pinata.Pinata) { card = stick.PathString(p, "number") },
+		"bank": func(p pinata.Pinata) { iban = stick.PathString(p, "iban") },
+	}
+
+	stick.Switch(decode(t, `{"type": "card", "number": "4111"}`), "type", variants)
+	if err := stick.ClearError(); err != nil || card != "4111" || iban != "" {
+		t.Fatal("the card variant must be chosen", err)
+	}
+
+	stick.Switch(decode(t, `{"type": "bank", "iban": 42}`), "type", variants)
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("a numeric iban must result in an error")
+	}
+	const expected = `pinata: incompatible type (this is not a string) at PathString("iban") at Switch("type", "bank")`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	for message, expected := range map[string]string{
+		`{"type": "cash"}`: `pinata: unexpected ("cash" is not a known variant, it must be one of "bank", "card") at Switch("type")`,
+		`{"kind": "card"}`: `pinata: not found ("type" does not exist, it must be one of "bank", "card") at Switch("type")`,
+		`{"type": 1}`:      `pinata: incompatible type (this is not a string) at Switch("type")`,
+	} {
+		stick.Switch(decode(t, message), "type", variants)
+		err := stick.ClearError()
+		if err == nil {
+			t.Errorf("%s must result in an error", message)
+		} else if err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package pinata
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// UnixSeconds is a layout for Stick.Time that reads the number of seconds
+	// since the Unix epoch.
+	UnixSeconds = "unix"
+	// UnixMilliseconds is a layout for Stick.Time that reads the number of
+	// milliseconds since the Unix epoch.
+	UnixMilliseconds = "unixms"
+)
+
+func fromEpochInt(v int64, layout string) time.Time {
+	if layout == UnixMilliseconds {
+		return time.UnixMilli(v)
+	}
+	return time.Unix(v, 0)
+}
+
+func fromEpoch(v float64, layout string) time.Time {
+	if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+		return fromEpochInt(int64(v), layout)
+	}
+	unit := 1e9
+	if layout == UnixMilliseconds {
+		unit = 1e6
+	}
+	whole := math.Floor(v)
+	return fromEpochInt(int64(whole), layout).Add(time.Duration(math.Round((v - whole) * unit)))
+}
+
+// parseEpoch parses a number of seconds or milliseconds (the bool indicates
+// success). Integers and decimal fractions are read exactly, other numbers
+// such as 1.7e9 as a float64.
+func parseEpoch(str, layout string) (time.Time, bool) {
+	if v, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return fromEpochInt(v, layout), true
+	}
+	digits := 9
+	if layout == UnixMilliseconds {
+		digits = 6
+	}
+	if whole, fraction, ok := strings.Cut(str, "."); ok && fraction != "" && strings.Trim(fraction, "0123456789") == "" {
+		v, err := strconv.ParseInt(whole, 10, 64)
+		if err == nil {
+			// digits beyond a nanosecond are dropped
+			fraction = (fraction + strings.Repeat("0", digits))[:digits]
+			ns, _ := strconv.ParseInt(fraction, 10, 64)
+			if strings.HasPrefix(whole, "-") {
+				ns = -ns
+			}
+			return fromEpochInt(v, layout).Add(time.Duration(ns)), true
+		}
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+		return time.Time{}, false
+	}
+	return fromEpoch(v, layout), true
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalTime(p Pinata, methodName string, input func() []interface{}, layout string) time.Time {
+	p = s.unwrap(p, methodName, input)
+	if _, ok := p.Map(); ok {
+		s.unsupported(p, methodName, input, "this is a map")
+		return time.Time{}
+	}
+	if _, ok := p.Slice(); ok {
+		s.unsupported(p, methodName, input, "this is a slice")
+		return time.Time{}
+	}
+	switch v := p.Value().(type) {
+	case time.Time:
+		return v
+	case float64:
+		if layout == UnixSeconds || layout == UnixMilliseconds {
+			return fromEpoch(v, layout)
+		}
+		s.unsupported(p, methodName, input, fmt.Sprintf("this is a number, use the %q or %q layout", UnixSeconds, UnixMilliseconds))
+		return time.Time{}
+	case string:
+		if layout == UnixSeconds || layout == UnixMilliseconds {
+			t, ok := parseEpoch(v, layout)
+			if !ok {
+				s.invalidFormat(p, methodName, input, fmt.Sprintf("%q does not match layout %q", v, layout))
+				return time.Time{}
+			}
+			return t
+		}
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, v)
+		if err != nil {
+			s.invalidFormat(p, methodName, input, fmt.Sprintf("%q does not match layout %q", v, layout))
+			return time.Time{}
+		}
+		return t
+	}
+	s.unsupported(p, methodName, input, "this is not a time")
+	return time.Time{}
+}
+
+// this method assumes s.err != nil
+func (s *stick) internalDuration(p Pinata, methodName string, input func() []interface{}) time.Duration {
+	p = s.unwrap(p, methodName, input)
+	if _, ok := p.Map(); ok {
+		s.unsupported(p, methodName, input, "this is a map")
+		return 0
+	}
+	if _, ok := p.Slice(); ok {
+		s.unsupported(p, methodName, input, "this is a slice")
+		return 0
+	}
+	switch v := p.Value().(type) {
+	case time.Duration:
+		return v
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			s.invalidFormat(p, methodName, input, fmt.Sprintf(`%q is not a duration such as "1h30m"`, v))
+			return 0
+		}
+		return d
+	}
+	s.unsupported(p, methodName, input, "this is not a duration")
+	return 0
+}
+
+func (s *stick) Time(p Pinata, layout string) time.Time {
+	if s.err != nil {
+		return time.Time{}
+	}
+	return s.internalTime(p, "Time", func() []interface{} { return []interface{}{layout} }, layout)
+}
+
+func (s *stick) IndexTime(p Pinata, layout string, index int) time.Time {
+	if s.err != nil {
+		return time.Time{}
+	}
+	const methodName = "IndexTime"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return time.Time{}
+	}
+	pinata.context = p.context
+	return s.internalTime(pinata, methodName, func() []interface{} { return []interface{}{layout, index} }, layout)
+}
+
+func (s *stick) PathTime(p Pinata, layout string, path ...string) time.Time {
+	if s.err != nil {
+		return time.Time{}
+	}
+	const methodName = "PathTime"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return time.Time{}
+	}
+	pinata.context = p.context
+	return s.internalTime(pinata, methodName, func() []interface{} {
+		return append([]interface{}{layout}, toInterfaceSlice(path)...)
+	}, layout)
+}
+
+func (s *stick) Duration(p Pinata) time.Duration {
+	if s.err != nil {
+		return 0
+	}
+	return s.internalDuration(p, "Duration", func() []interface{} { return nil })
+}
+
+func (s *stick) IndexDuration(p Pinata, index int) time.Duration {
+	if s.err != nil {
+		return 0
+	}
+	const methodName = "IndexDuration"
+	pinata := s.internalIndex(p, methodName, index)
+	if s.err != nil {
+		return 0
+	}
+	pinata.context = p.context
+	return s.internalDuration(pinata, methodName, func() []interface{} { return []interface{}{index} })
+}
+
+func (s *stick) PathDuration(p Pinata, path ...string) time.Duration {
+	if s.err != nil {
+		return 0
+	}
+	const methodName = "PathDuration"
+	pinata := s.internalPath(p, methodName, path...)
+	if s.err != nil {
+		return 0
+	}
+	pinata.context = p.context
+	return s.internalDuration(pinata, methodName, func() []interface{} { return toInterfaceSlice(path) })
+}
@@ -0,0 +1,106 @@
+package pinata_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestTime(t *testing.T) {
+	stick := pinata.NewStick()
+	p := pinata.NewPinata(map[string]interface{}{
+		"Created": "2016-03-01T12:30:00Z",
+		"Born":    "1984-02-29",
+		"Seen":    float64(1456835400),
+		"SeenMs":  "1456835400500",
+		"Native":  time.Date(2016, 3, 1, 12, 30, 0, 0, time.UTC),
+		"Timeout": "1m30s",
+		"Dates":   []interface{}{"2016-03-01"},
+	})
+	expected := time.Date(2016, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	if v := stick.PathTime(p, "", "Created"); !v.Equal(expected) {
+		t.Error("Created must be parsed as RFC 3339, got", v)
+	}
+	if v := stick.PathTime(p, "2006-01-02", "Born"); !v.Equal(time.Date(1984, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Born must be parsed with a custom layout, got", v)
+	}
+	if v := stick.PathTime(p, pinata.UnixSeconds, "Seen"); !v.Equal(expected) {
+		t.Error("Seen must be parsed as epoch seconds, got", v)
+	}
+	if v := stick.PathTime(p, pinata.UnixMilliseconds, "SeenMs"); !v.Equal(expected.Add(500 * time.Millisecond)) {
+		t.Error("SeenMs must be parsed as epoch milliseconds, got", v)
+	}
+	if v := stick.PathTime(p, "", "Native"); !v.Equal(expected) {
+		t.Error("native time values must be accepted, got", v)
+	}
+	if v := stick.IndexTime(stick.Path(p, "Dates"), "2006-01-02", 0); !v.Equal(time.Date(2016, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("index 0 of Dates must be parsed, got", v)
+	}
+	if v := stick.PathDuration(p, "Timeout"); v != 90*time.Second {
+		t.Error("Timeout must be parsed as a duration, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	stick.PathTime(p, "", "Born")
+	err := stick.ClearError()
+	if err == nil {
+		t.Fatal("a date must not parse as RFC 3339")
+	}
+	const expectedErr = `pinata: invalid format ("1984-02-29" does not match layout "2006-01-02T15:04:05Z07:00") at PathTime("", "Born")`
+	if err.Error() != expectedErr {
+		t.Errorf("expected %q, got %q", expectedErr, err.Error())
+	}
+
+	stick.PathTime(p, "", "Seen")
+	if err := stick.ClearError(); err == nil {
+		t.Error("a number must require an epoch layout")
+	}
+
+	stick.PathDuration(p, "Created")
+	if err := stick.ClearError(); err == nil {
+		t.Error("a time must not parse as a duration")
+	} else if err.(*pinata.Error).Reason() != pinata.ErrorReasonInvalidFormat {
+		t.Error("error reason must be invalid format")
+	}
+}
+
+func TestTimeEpochPrecision(t *testing.T) {
+	stick := pinata.NewStick()
+	p := pinata.NewPinata(map[string]interface{}{
+		"Ms":         float64(1700000000123),
+		"MsString":   "1700000000123",
+		"Fraction":   "1700000000.001",
+		"Negative":   "-1.5",
+		"FloatSecs":  1700000000.5,
+		"Exponent":   "1.7e9",
+		"MsFraction": "1700000000123.5",
+	})
+	for _, test := range []struct {
+		path     string
+		layout   string
+		expected time.Time
+	}{
+		{"Ms", pinata.UnixMilliseconds, time.UnixMilli(1700000000123)},
+		{"MsString", pinata.UnixMilliseconds, time.UnixMilli(1700000000123)},
+		{"Fraction", pinata.UnixSeconds, time.Unix(1700000000, 1000000)},
+		{"Negative", pinata.UnixSeconds, time.Unix(-2, 500000000)},
+		{"FloatSecs", pinata.UnixSeconds, time.Unix(1700000000, 500000000)},
+		{"Exponent", pinata.UnixSeconds, time.Unix(1700000000, 0)},
+		{"MsFraction", pinata.UnixMilliseconds, time.UnixMilli(1700000000123).Add(500 * time.Microsecond)},
+	} {
+		v := stick.PathTime(p, test.layout, test.path)
+		if err := stick.ClearError(); err != nil {
+			t.Fatal(err)
+		}
+		if v.UnixNano() != test.expected.UnixNano() {
+			t.Errorf("%s: expected %d, got %d", test.path, test.expected.UnixNano(), v.UnixNano())
+		}
+	}
+	if v := stick.PathTime(p, pinata.UnixMilliseconds, "Ms"); v.UnixMilli() != 1700000000123 {
+		t.Errorf("expected 1700000000123, got %d", v.UnixMilli())
+	}
+}
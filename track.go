@@ -0,0 +1,109 @@
+package pinata
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// slot identifies a map key or slice index within a document.
+type slot struct {
+	container uintptr
+	key       string
+}
+
+// slotOf returns the slot for key within a map or slice.
+func slotOf(container interface{}, key string) slot {
+	return slot{reflect.ValueOf(container).Pointer(), key}
+}
+
+// visit identifies a map key or slice index read through a tracking Stick.
+// Unlike a slot it includes the length of a slice, so slices sharing a
+// backing array are told apart.
+type visit struct {
+	slot
+	length int
+}
+
+func mapVisit(m map[string]interface{}, key string) visit {
+	return visit{slotOf(m, key), -1}
+}
+
+func sliceVisit(slice []interface{}, index int) visit {
+	return visit{slotOf(slice, strconv.Itoa(index)), len(slice)}
+}
+
+// WithTracking makes the Stick record every map key and slice index it reads,
+// see Stick.Unvisited. The Stick keeps the maps and slices it read from
+// alive, so a Stick can track several documents.
+func WithTracking() Option {
+	return func(s *stick) {
+		s.visited = make(map[visit]bool)
+		s.containers = make(map[slot]interface{})
+	}
+}
+
+func (s *stick) visitKey(m map[string]interface{}, key string) {
+	if s.visited != nil {
+		v := mapVisit(m, key)
+		s.visited[v] = true
+		// referencing the map ensures its address is not reused by another
+		s.containers[slot{container: v.container}] = m
+	}
+}
+
+func (s *stick) visitIndex(slice []interface{}, index int) {
+	if s.visited != nil {
+		v := sliceVisit(slice, index)
+		s.visited[v] = true
+		s.containers[slot{container: v.container}] = slice
+	}
+}
+
+func (s *stick) unvisited(value interface{}, location []string, result []string) []string {
+	switch t := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if s.visited[mapVisit(t, k)] {
+				result = s.unvisited(t[k], appendToken(location, k), result)
+			} else {
+				result = append(result, formatPointer(appendToken(location, k)))
+			}
+		}
+	case []interface{}:
+		for i := range t {
+			token := strconv.Itoa(i)
+			if s.visited[sliceVisit(t, i)] {
+				result = s.unvisited(t[i], appendToken(location, token), result)
+			} else {
+				result = append(result, formatPointer(appendToken(location, token)))
+			}
+		}
+	}
+	return result
+}
+
+func (s *stick) Unvisited(p Pinata) []string {
+	if s.err != nil {
+		return nil
+	}
+	if s.visited == nil {
+		s.err = &Error{
+			context: &ErrorContext{
+				methodName: "Unvisited",
+				methodArgs: func() []interface{} { return nil },
+				source:     p.source,
+				next:       p.context,
+			},
+			reason: ErrorReasonInvalidInput,
+			advice: "create the stick with WithTracking",
+		}
+		return nil
+	}
+	return s.unvisited(p.Value(), nil, nil)
+}
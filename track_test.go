@@ -0,0 +1,80 @@
+package pinata_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestUnvisited(t *testing.T) {
+	_, thePinata := start(t)
+	stick := pinata.NewStick(pinata.WithTracking())
+
+	stick.PathString(thePinata, "Name")
+	stick.IndexString(stick.Path(thePinata, "Phone"), 0)
+	stick.PathString(thePinata, "Address", "Street")
+	indoors := stick.Path(stick.Index(stick.Path(thePinata, "Hobbies"), 0), "Indoors")
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"/Address/City",
+		"/Hobbies/0/Indoors/0",
+		"/Hobbies/0/Indoors/1",
+		"/Hobbies/0/Indoors/2",
+		"/Hobbies/0/Outdoors",
+		"/Phone/1",
+	}
+	if unvisited := stick.Unvisited(thePinata); !reflect.DeepEqual(unvisited, expected) {
+		t.Errorf("expected %v, got %v", expected, unvisited)
+	}
+
+	expected = []string{"/0", "/1", "/2"}
+	if unvisited := stick.Unvisited(indoors); !reflect.DeepEqual(unvisited, expected) {
+		t.Errorf("expected %v, got %v", expected, unvisited)
+	}
+
+	untracked := pinata.NewStick()
+	untracked.Unvisited(thePinata)
+	if err := untracked.ClearError(); err == nil {
+		t.Error("a stick without tracking must result in an error")
+	}
+}
+
+func TestUnvisitedIsolation(t *testing.T) {
+	stick := pinata.NewStick(pinata.WithTracking())
+
+	backing := []interface{}{"a", "b", "c"}
+	stick.Index(pinata.NewPinata(backing[:2]), 0)
+	expected := []string{"/0", "/1", "/2"}
+	if unvisited := stick.Unvisited(pinata.NewPinata(backing)); !reflect.DeepEqual(unvisited, expected) {
+		t.Errorf("slices sharing a backing array must be tracked apart, expected %v, got %v", expected, unvisited)
+	}
+
+	p := pinata.NewPinata(map[string]interface{}{
+		"user":     map[string]interface{}{"id": 1.0},
+		"username": "x",
+	})
+	stick.FirstOf(p, pinata.P("user", "name"), pinata.P("username"))
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"/user"}
+	if unvisited := stick.Unvisited(p); !reflect.DeepEqual(unvisited, expected) {
+		t.Errorf("failed FirstOf attempts must not count as read, expected %v, got %v", expected, unvisited)
+	}
+
+	for i := 0; i < 100; i++ {
+		doc := pinata.NewPinata(map[string]interface{}{"a": 1.0, "b": 2.0})
+		stick.Path(doc, "a")
+		expected = []string{"/b"}
+		if unvisited := stick.Unvisited(doc); !reflect.DeepEqual(unvisited, expected) {
+			t.Fatalf("document %d: expected %v, got %v", i, expected, unvisited)
+		}
+		if unvisited := stick.Unvisited(pinata.NewPinata(map[string]interface{}{"a": 1.0})); len(unvisited) != 1 {
+			t.Fatalf("document %d: a new document must not count as read, got %v", i, unvisited)
+		}
+	}
+}
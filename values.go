@@ -0,0 +1,216 @@
+package pinata
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValuesOptions configures FromValuesWith.
+type ValuesOptions struct {
+	// Multi makes every value a slice holding all of the values given for its
+	// key, instead of a string holding the first one.
+	Multi bool
+}
+
+// FromValues is FromValuesWith using the default options.
+func FromValues(values url.Values) (Pinata, error) {
+	return FromValuesWith(ValuesOptions{}, values)
+}
+
+// FromValuesWith builds a Pinata from query string or form values. Keys are
+// split into a path using dots and brackets, so a.b=c becomes
+// {"a": {"b": "c"}} and items[0][name]=x becomes {"items": [{"name": "x"}]}.
+// Numeric keys below the top level create slices, ordered by index without
+// gaps, and a key ending in [] appends each of its values to a slice. The
+// Pinata itself always holds a map. The source of each value
+// is its original key so errors name it.
+//
+// The error is a *Error if a key is malformed or two keys conflict, for
+// example a=1 and a[b]=2.
+func FromValuesWith(opts ValuesOptions, values url.Values) (Pinata, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := &valuesNode{root: true}
+	for _, key := range keys {
+		path, ok := splitValuesKey(key)
+		if !ok {
+			return Pinata{}, valuesError(key, fmt.Sprintf("%q is not a valid key", key))
+		}
+		if err := root.insert(key, path, values[key], opts); err != nil {
+			return Pinata{}, err
+		}
+	}
+
+	sources := make(map[slot]string)
+	p := NewPinata(root.build(sources))
+	p.sources = sources
+	return p, nil
+}
+
+func valuesError(key, advice string) *Error {
+	return &Error{
+		context: &ErrorContext{
+			methodName: "FromValues",
+			methodArgs: func() []interface{} { return nil },
+			source:     key,
+		},
+		reason: ErrorReasonInvalidInput,
+		advice: advice,
+	}
+}
+
+// splitValuesKey splits a key such as a.b[0][c] into its path (the bool
+// indicates success). An empty bracket results in an empty element.
+func splitValuesKey(key string) ([]string, bool) {
+	if key == "" || key[0] == '[' || key[0] == '.' {
+		return nil, false
+	}
+	var path []string
+	for key != "" {
+		switch key[0] {
+		case '[':
+			end := strings.IndexByte(key, ']')
+			if end < 0 {
+				return nil, false
+			}
+			path = append(path, key[1:end])
+			key = key[end+1:]
+		case '.':
+			key = key[1:]
+			if key == "" || key[0] == '.' || key[0] == '[' {
+				return nil, false
+			}
+		default:
+			end := strings.IndexAny(key, ".[")
+			if end < 0 {
+				end = len(key)
+			}
+			path = append(path, key[:end])
+			key = key[end:]
+		}
+	}
+	return path, true
+}
+
+type valuesNode struct {
+	// root is always a map, so a top-level key such as 0 is a map key
+	root     bool
+	leaf     bool
+	value    interface{}
+	source   string
+	list     bool
+	children map[string]*valuesNode
+	appended []*valuesNode
+}
+
+func isIndex(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *valuesNode) insert(key string, path []string, values []string, opts ValuesOptions) error {
+	if n.leaf {
+		return valuesError(key, fmt.Sprintf("%q conflicts with %q", key, n.source))
+	}
+	token := path[0]
+	list := !n.root && (token == "" || isIndex(token))
+	if n.children != nil || n.appended != nil {
+		if n.list != list {
+			return valuesError(key, fmt.Sprintf("%q mixes indices and names", key))
+		}
+	}
+	n.list = list
+
+	if token == "" {
+		if len(path) > 1 {
+			return valuesError(key, fmt.Sprintf("%q can only use [] at the end", key))
+		}
+		for _, v := range values {
+			n.appended = append(n.appended, &valuesNode{leaf: true, value: v, source: key})
+		}
+		return nil
+	}
+
+	if n.children == nil {
+		n.children = make(map[string]*valuesNode)
+	}
+	if list {
+		// normalise indices such as 01 so they address the same element as 1
+		i, err := strconv.Atoi(token)
+		if err != nil {
+			return valuesError(key, fmt.Sprintf("%q has an index that is too large", key))
+		}
+		token = strconv.Itoa(i)
+	}
+	child, ok := n.children[token]
+	if !ok {
+		child = &valuesNode{}
+		n.children[token] = child
+	}
+	if len(path) > 1 {
+		return child.insert(key, path[1:], values, opts)
+	}
+	if child.leaf || child.children != nil || child.appended != nil {
+		return valuesError(key, fmt.Sprintf("%q conflicts with another key", key))
+	}
+	child.leaf = true
+	child.source = key
+	if opts.Multi {
+		child.value = toInterfaceSlice(values)
+	} else if len(values) > 0 {
+		child.value = values[0]
+	} else {
+		child.value = ""
+	}
+	return nil
+}
+
+// build returns the value for the node, recording the source of leaves.
+func (n *valuesNode) build(sources map[slot]string) interface{} {
+	if n.leaf {
+		return n.value
+	}
+	if !n.list {
+		m := make(map[string]interface{}, len(n.children))
+		for k, child := range n.children {
+			m[k] = child.build(sources)
+			if child.leaf {
+				sources[slotOf(m, k)] = child.source
+			}
+		}
+		return m
+	}
+	indices := make([]int, 0, len(n.children))
+	for k := range n.children {
+		i, _ := strconv.Atoi(k)
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	children := make([]*valuesNode, 0, len(indices)+len(n.appended))
+	for _, i := range indices {
+		children = append(children, n.children[strconv.Itoa(i)])
+	}
+	children = append(children, n.appended...)
+	s := make([]interface{}, len(children))
+	for i, child := range children {
+		s[i] = child.build(sources)
+		if child.leaf {
+			sources[slotOf(s, strconv.Itoa(i))] = child.source
+		}
+	}
+	return s
+}
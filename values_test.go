@@ -0,0 +1,85 @@
+package pinata_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestFromValues(t *testing.T) {
+	values, err := url.ParseQuery("items[1][name]=b&items[0][name]=a&items[0][qty]=2&a.b=c&tags[]=x&tags[]=y&sort=asc&sort=desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := pinata.FromValues(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decode(t, `{
+		"items": [{"name": "a", "qty": "2"}, {"name": "b"}],
+		"a": {"b": "c"},
+		"tags": ["x", "y"],
+		"sort": "asc"
+	}`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), p.Value())
+	}
+
+	stick := pinata.NewStick()
+	stick.PathFloat64(stick.Index(stick.Path(p, "items"), 0), "qty")
+	err = stick.ClearError()
+	if err == nil {
+		t.Fatal("qty must not be a number")
+	}
+	if source := err.(*pinata.Error).Source(); source != "items[0][qty]" {
+		t.Errorf("error must name items[0][qty], got %q", source)
+	}
+
+	p, err = pinata.FromValuesWith(pinata.ValuesOptions{Multi: true}, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = decode(t, `["asc", "desc"]`)
+	if sort := stick.Path(p, "sort"); !pinata.Equal(sort, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), sort.Value())
+	}
+}
+
+func TestFromValuesNumericRoot(t *testing.T) {
+	p, err := pinata.FromValues(url.Values{"0": {"a"}, "01": {"b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stick := pinata.NewStick()
+	if v := stick.PathString(p, "0"); v != "a" {
+		t.Error("a top-level numeric key must be a map key, got", v)
+	}
+	if v := stick.PathString(p, "01"); v != "b" {
+		t.Error("a top-level numeric key must be kept as it is, got", v)
+	}
+	if err := stick.ClearError(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFromValuesErrors(t *testing.T) {
+	for _, query := range []string{
+		"a=1&a[b]=2",
+		"a[0]=1&a[b]=2",
+		"a[=1",
+		"a..b=1",
+		"[x]=1",
+		"a[][b]=1",
+	} {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pinata.FromValues(values); err == nil {
+			t.Errorf("%s must result in an error", query)
+		} else if _, ok := err.(*pinata.Error); !ok {
+			t.Errorf("%s must result in a *pinata.Error, got %T", query, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package pinata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// XMLOptions configures ParseXML.
+type XMLOptions struct {
+	// ForceArray lists the elements that always become a slice, even when they
+	// occur once, as JSON Pointers of element names such as "/catalog/book".
+	ForceArray []string
+}
+
+// ParseXML converts an XML document into a Pinata holding a map with the root
+// element as its only key. An element becomes a string holding its text if it
+// has neither attributes nor child elements. Otherwise it becomes a map where
+// each attribute is stored as "@name", each child element under its name and
+// any text other than white space as "#text". Child elements that occur more
+// than once, or that are listed in XMLOptions.ForceArray, become a slice.
+// Namespaces are dropped from names.
+//
+// The source of each value is the line it was read from, such as "line 12",
+// so errors name it.
+//
+// The error is a *Error if the document is malformed or has more than one
+// root element.
+func ParseXML(r io.Reader, opts XMLOptions) (Pinata, error) {
+	forceArray := make(map[string]bool, len(opts.ForceArray))
+	for _, path := range opts.ForceArray {
+		forceArray[path] = true
+	}
+
+	decoder := xml.NewDecoder(r)
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Pinata{}, xmlError(err)
+		}
+		line, _ := decoder.InputPos()
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local, line: line}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				node.attrs = append(node.attrs, xml.Attr{Name: xml.Name{Local: attr.Name.Local}, Value: attr.Value})
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			} else {
+				return Pinata{}, xmlError(&xml.SyntaxError{Msg: fmt.Sprintf("a second root element <%s> starts", node.name), Line: line})
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				_, _ = stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+	if root == nil {
+		return Pinata{}, xmlError(fmt.Errorf("the document has no root element"))
+	}
+
+	sources := make(map[slot]string)
+	contents := map[string]interface{}{}
+	contents[root.name] = root.build([]string{root.name}, forceArray, sources)
+	sources[slotOf(contents, root.name)] = root.source()
+	p := NewPinata(contents)
+	p.sources = sources
+	return p, nil
+}
+
+func xmlError(err error) *Error {
+	source := ""
+	advice := err.Error()
+	if syntaxErr, ok := err.(*xml.SyntaxError); ok {
+		source = "line " + strconv.Itoa(syntaxErr.Line)
+		advice = syntaxErr.Msg
+	}
+	return &Error{
+		context: &ErrorContext{
+			methodName: "ParseXML",
+			methodArgs: func() []interface{} { return nil },
+			source:     source,
+		},
+		reason: ErrorReasonInvalidInput,
+		advice: advice,
+	}
+}
+
+type xmlNode struct {
+	name     string
+	line     int
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     strings.Builder
+}
+
+func (n *xmlNode) source() string {
+	return "line " + strconv.Itoa(n.line)
+}
+
+// build returns the value for the element at the given location, recording
+// the source of every value it holds.
+func (n *xmlNode) build(location []string, forceArray map[string]bool, sources map[slot]string) interface{} {
+	text := n.text.String()
+	if len(n.attrs) == 0 && len(n.children) == 0 {
+		return text
+	}
+
+	m := make(map[string]interface{}, len(n.attrs)+len(n.children)+1)
+	for _, attr := range n.attrs {
+		key := "@" + attr.Name.Local
+		m[key] = attr.Value
+		sources[slotOf(m, key)] = n.source()
+	}
+	if strings.TrimSpace(text) != "" {
+		m["#text"] = text
+		sources[slotOf(m, "#text")] = n.source()
+	}
+
+	// group the children by name, keeping the order of first occurrence
+	var names []string
+	groups := make(map[string][]*xmlNode)
+	for _, child := range n.children {
+		if _, ok := groups[child.name]; !ok {
+			names = append(names, child.name)
+		}
+		groups[child.name] = append(groups[child.name], child)
+	}
+	for _, name := range names {
+		group := groups[name]
+		childLocation := appendToken(location, name)
+		if len(group) == 1 && !forceArray[formatPointer(childLocation)] {
+			m[name] = group[0].build(childLocation, forceArray, sources)
+			sources[slotOf(m, name)] = group[0].source()
+			continue
+		}
+		s := make([]interface{}, len(group))
+		for i, child := range group {
+			s[i] = child.build(childLocation, forceArray, sources)
+			sources[slotOf(s, strconv.Itoa(i))] = child.source()
+		}
+		m[name] = s
+		sources[slotOf(m, name)] = group[0].source()
+	}
+	return m
+}
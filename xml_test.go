@@ -0,0 +1,91 @@
+package pinata_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robbiev/pinata"
+)
+
+func TestParseXML(t *testing.T) {
+	const document = `<?xml version="1.0"?>
+<catalog xmlns="urn:example:catalog" updated="2024-01-01">
+  <book id="1">
+    <title>Go</title>
+    <price currency="EUR">twelve</price>
+  </book>
+  <book id="2">
+    <title>XML</title>
+  </book>
+  <shelf><label>A</label></shelf>
+  <note/>
+</catalog>`
+
+	p, err := pinata.ParseXML(strings.NewReader(document), pinata.XMLOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := decode(t, `{"catalog": {
+		"@updated": "2024-01-01",
+		"book": [
+			{"@id": "1", "title": "Go", "price": {"@currency": "EUR", "#text": "twelve"}},
+			{"@id": "2", "title": "XML"}
+		],
+		"shelf": {"label": "A"},
+		"note": ""
+	}}`)
+	if !pinata.Equal(p, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), p.Value())
+	}
+
+	stick := pinata.NewStick(pinata.WithCoercion(pinata.CoerceFloat64))
+	book := stick.Index(stick.Path(p, "catalog", "book"), 0)
+	stick.PathFloat64(book, "price", "#text")
+	err = stick.ClearError()
+	if err == nil {
+		t.Fatal("price must not be a number")
+	}
+	if source := err.(*pinata.Error).Source(); source != "line 5" {
+		t.Errorf("error must name line 5, got %q", source)
+	}
+
+	p, err = pinata.ParseXML(strings.NewReader(document), pinata.XMLOptions{
+		ForceArray: []string{"/catalog/shelf"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = decode(t, `[{"label": "A"}]`)
+	if shelf := stick.Path(p, "catalog", "shelf"); !pinata.Equal(shelf, expected) {
+		t.Errorf("expected %v, got %v", expected.Value(), shelf.Value())
+	}
+}
+
+func TestParseXMLErrors(t *testing.T) {
+	for _, document := range []string{
+		"",
+		"<a>\n<b></a>",
+		"<a>unterminated",
+		"<a>1</a><b>2</b>",
+	} {
+		_, err := pinata.ParseXML(strings.NewReader(document), pinata.XMLOptions{})
+		if err == nil {
+			t.Errorf("%q must result in an error", document)
+		} else if _, ok := err.(*pinata.Error); !ok {
+			t.Errorf("%q must result in a *pinata.Error, got %T", document, err)
+		}
+	}
+
+	_, err := pinata.ParseXML(strings.NewReader("<a>\n<b></a>"), pinata.XMLOptions{})
+	if source := err.(*pinata.Error).Source(); source != "line 2" {
+		t.Errorf("error must name line 2, got %q", source)
+	}
+
+	_, err = pinata.ParseXML(strings.NewReader("<a>1</a>\n<b>2</b>"), pinata.XMLOptions{})
+	if err == nil {
+		t.Fatal("a second root element must result in an error")
+	}
+	if source := err.(*pinata.Error).Source(); source != "line 2" {
+		t.Errorf("error must name line 2, got %q", source)
+	}
+}